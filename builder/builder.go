@@ -0,0 +1,312 @@
+// Package builder creates modules from Go values, e.g. the fixtures of tests
+package builder
+
+import (
+	"fmt"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/operator"
+	"github.com/LBruyne/wasm-decode/types"
+)
+
+// Builder builds a module section by section. The Add and Import functions return the index
+// of what they add in its index space, where the imported components come first, so every import
+// of a kind must be added before the definitions of the same kind.
+//
+// Errors are kept until Module or Bytes, so that the calls can be chained.
+type Builder struct {
+	m   *types.Module
+	err error
+}
+
+// New returns a builder of an empty module
+func New() *Builder {
+	return &Builder{m: &types.Module{}}
+}
+
+// Limits returns the limits of a memory or a table of min pages or elements and no max
+func Limits(min uint32) *types.LimitType {
+	return &types.LimitType{Tag: types.LimitTypeOnlyMin, Min: min}
+}
+
+// LimitsMax returns the limits of a memory or a table from min up to max pages or elements
+func LimitsMax(min, max uint32) *types.LimitType {
+	return &types.LimitType{Tag: types.LimitTypeBothMinAndMax, Min: min, Max: max}
+}
+
+// fail records the first error
+func (b *Builder) fail(format string, args ...interface{}) {
+	if b.err == nil {
+		b.err = fmt.Errorf(format, args...)
+	}
+}
+
+// AddType adds the function type of params and results, unless it is added already, and returns its index
+func (b *Builder) AddType(params, results []types.ValueType) uint32 {
+	for i, ft := range b.m.SecType {
+		if equalValueTypes(ft.InputType, params) && equalValueTypes(ft.ReturnType, results) {
+			return uint32(i)
+		}
+	}
+
+	b.m.SecType = append(b.m.SecType, &types.FunctionType{
+		InputType:  append([]types.ValueType{}, params...),
+		ReturnType: append([]types.ValueType{}, results...),
+	})
+	return uint32(len(b.m.SecType) - 1)
+}
+
+func equalValueTypes(a, b []types.ValueType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// addImport adds the import of desc, defined is the number of definitions of its kind
+func (b *Builder) addImport(module, name string, desc types.External, defined int) uint32 {
+	kind := desc.Kind()
+	if defined != 0 {
+		b.fail("import %s.%s: %v imported after %d defined", module, name, kind, defined)
+	}
+
+	idx := b.m.ImportedCount(kind)
+	b.m.SecImport = append(b.m.SecImport, &types.ImportSegment{Module: module, Name: name, Desc: desc})
+	return idx
+}
+
+// ImportFunc imports the function of the type at typeIdx and returns its function index
+func (b *Builder) ImportFunc(module, name string, typeIdx uint32) uint32 {
+	if int(typeIdx) >= len(b.m.SecType) {
+		b.fail("%w: import %s.%s: type %d of %d", common.ErrIndexOutOfRange, module, name, typeIdx, len(b.m.SecType))
+	}
+	return b.addImport(module, name, &types.FuncImport{TypeIndex: typeIdx}, len(b.m.SecFunction))
+}
+
+// ImportTable imports a table of elemType and returns its table index
+func (b *Builder) ImportTable(module, name string, elemType types.ValueType, limits *types.LimitType) uint32 {
+	tt := &types.TableType{ElemType: elemType, Limit: limits}
+	return b.addImport(module, name, &types.TableImport{Type: tt}, len(b.m.SecTable))
+}
+
+// ImportMemory imports a memory and returns its memory index
+func (b *Builder) ImportMemory(module, name string, limits *types.LimitType) uint32 {
+	return b.addImport(module, name, &types.MemoryImport{Type: limits}, len(b.m.SecMemory))
+}
+
+// ImportGlobal imports a global and returns its global index
+func (b *Builder) ImportGlobal(module, name string, vt types.ValueType, mutable bool) uint32 {
+	gt := &types.GlobalType{Value: vt, Mutable: mutable}
+	return b.addImport(module, name, &types.GlobalImport{Type: gt}, len(b.m.SecGlobal))
+}
+
+// AddFunction adds a function of the type at typeIdx and returns its function index.
+// The locals follow the parameters, body is the instructions ended by the end of the function,
+// e.g. assembled by operator.Assembler.
+func (b *Builder) AddFunction(typeIdx uint32, locals []types.ValueType, body []byte) uint32 {
+	if int(typeIdx) >= len(b.m.SecType) {
+		b.fail("%w: add function: type %d of %d", common.ErrIndexOutOfRange, typeIdx, len(b.m.SecType))
+	}
+
+	// consecutive locals of the same type are declared at once
+	var decls []*types.LocalValueType
+	for _, vt := range locals {
+		if n := len(decls); n > 0 && decls[n-1].Type == vt {
+			decls[n-1].Count++
+			continue
+		}
+		decls = append(decls, &types.LocalValueType{Count: 1, Type: vt})
+	}
+
+	if n := len(body); n == 0 || body[n-1] != byte(operator.OpCodeEnd) {
+		b.fail("add function: body not ended by %#x", byte(operator.OpCodeEnd))
+	}
+
+	b.m.SecFunction = append(b.m.SecFunction, typeIdx)
+	b.m.SecCode = append(b.m.SecCode, &types.CodeSegment{
+		Locals:    decls,
+		NumLocals: uint32(len(locals)),
+		Body:      body,
+		BodySize:  uint32(len(body)),
+	})
+	return b.m.ImportedCount(types.ExternalKindFunc) + uint32(len(b.m.SecFunction)-1)
+}
+
+// AddTable adds a table of elemType and returns its table index
+func (b *Builder) AddTable(elemType types.ValueType, limits *types.LimitType) uint32 {
+	b.m.SecTable = append(b.m.SecTable, &types.TableType{ElemType: elemType, Limit: limits})
+	return b.m.ImportedCount(types.ExternalKindTable) + uint32(len(b.m.SecTable)-1)
+}
+
+// AddMemory adds a memory and returns its memory index
+func (b *Builder) AddMemory(limits *types.LimitType) uint32 {
+	b.m.SecMemory = append(b.m.SecMemory, limits)
+	return b.m.ImportedCount(types.ExternalKindMemory) + uint32(len(b.m.SecMemory)-1)
+}
+
+// AddGlobal adds a global initialized by init, e.g. types.NewI32Const(0), and returns its global index
+func (b *Builder) AddGlobal(vt types.ValueType, mutable bool, init *types.ConstExpression) uint32 {
+	if init == nil {
+		b.fail("add global: missing init")
+	} else if t := init.Type(); t != 0 && t != vt {
+		// the type of global.get is the one of the global it reads
+		b.fail("add global: init of type %v for %v", t, vt)
+	}
+	b.m.SecGlobal = append(b.m.SecGlobal, &types.GlobalSegment{
+		Type: &types.GlobalType{Value: vt, Mutable: mutable},
+		Init: init,
+	})
+	return b.m.ImportedCount(types.ExternalKindGlobal) + uint32(len(b.m.SecGlobal)-1)
+}
+
+// AddData adds an active data segment copying data into the memory at memIdx from offset,
+// and returns its data index
+func (b *Builder) AddData(memIdx uint32, offset int32, data []byte) uint32 {
+	b.m.SecData = append(b.m.SecData, &types.DataSegment{
+		Mode:     types.SegmentModeActive,
+		MemIdx:   memIdx,
+		Offset:   types.NewI32Const(offset),
+		Init:     data,
+		InitSize: uint32(len(data)),
+	})
+	return uint32(len(b.m.SecData) - 1)
+}
+
+// AddPassiveData adds a passive data segment, which is copied by memory.init, and returns its data index.
+// It declares the data count, which memory.init and data.drop require.
+func (b *Builder) AddPassiveData(data []byte) uint32 {
+	b.m.SecData = append(b.m.SecData, &types.DataSegment{
+		Mode:     types.SegmentModePassive,
+		Init:     data,
+		InitSize: uint32(len(data)),
+	})
+	return uint32(len(b.m.SecData) - 1)
+}
+
+// AddElements adds an active element segment putting the references to funcs into the table at tableIdx
+// from offset, and returns its element index
+func (b *Builder) AddElements(tableIdx uint32, offset int32, funcs ...uint32) uint32 {
+	b.m.SecElement = append(b.m.SecElement, &types.ElementSegment{
+		Mode:     types.SegmentModeActive,
+		TableIdx: tableIdx,
+		Offset:   types.NewI32Const(offset),
+		ElemType: types.ElemTypeFuncRef,
+		Init:     append([]uint32{}, funcs...),
+	})
+	return uint32(len(b.m.SecElement) - 1)
+}
+
+// export adds the export of name
+func (b *Builder) export(name string, kind types.ExternalKind, idx uint32) *Builder {
+	for _, exp := range b.m.SecExport {
+		if exp.Name == name {
+			b.fail("export %q: duplicate name", name)
+		}
+	}
+
+	b.m.SecExport = append(b.m.SecExport, &types.ExportSegment{
+		Name: name,
+		Desc: &types.ExportDescription{Kind: kind, Index: idx},
+	})
+	return b
+}
+
+// ExportFunc exports the function at funcIdx as name
+func (b *Builder) ExportFunc(name string, funcIdx uint32) *Builder {
+	return b.export(name, types.ExternalKindFunc, funcIdx)
+}
+
+// ExportTable exports the table at tableIdx as name
+func (b *Builder) ExportTable(name string, tableIdx uint32) *Builder {
+	return b.export(name, types.ExternalKindTable, tableIdx)
+}
+
+// ExportMemory exports the memory at memIdx as name
+func (b *Builder) ExportMemory(name string, memIdx uint32) *Builder {
+	return b.export(name, types.ExternalKindMemory, memIdx)
+}
+
+// ExportGlobal exports the global at globalIdx as name
+func (b *Builder) ExportGlobal(name string, globalIdx uint32) *Builder {
+	return b.export(name, types.ExternalKindGlobal, globalIdx)
+}
+
+// SetStart makes the function at funcIdx the start function
+func (b *Builder) SetStart(funcIdx uint32) *Builder {
+	b.m.SecStart = &funcIdx
+	return b
+}
+
+// AddCustom adds a custom section, which is written after the other sections
+func (b *Builder) AddCustom(name string, data []byte) *Builder {
+	c := &types.CustomSec{Name: name, Bytes: data}
+	b.m.SecCustoms = append(b.m.SecCustoms, c)
+	b.m.SecCustom = c
+	return b
+}
+
+// Module returns the module built so far, or the first error met
+func (b *Builder) Module() (*types.Module, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+
+	for _, d := range b.m.SecData {
+		if d.Mode == types.SegmentModePassive {
+			n := uint32(len(b.m.SecData))
+			b.m.SecDataCount = &n
+			break
+		}
+	}
+	return b.m, nil
+}
+
+// check looks for the references to indices which are not added, which fail with common.ErrIndexOutOfRange
+func (b *Builder) check() error {
+	m := b.m
+	for _, exp := range m.SecExport {
+		if _, err := m.ExportByName(exp.Name); err != nil {
+			return fmt.Errorf("%w: %v", common.ErrIndexOutOfRange, err)
+		}
+	}
+	if m.SecStart != nil {
+		if _, err := m.Function(*m.SecStart); err != nil {
+			return fmt.Errorf("%w: start: %v", common.ErrIndexOutOfRange, err)
+		}
+	}
+	for i, e := range m.SecElement {
+		if _, err := m.Table(e.TableIdx); err != nil {
+			return fmt.Errorf("%w: %v-th element segment: %v", common.ErrIndexOutOfRange, i, err)
+		}
+		for _, idx := range e.Init {
+			if _, err := m.Function(idx); err != nil {
+				return fmt.Errorf("%w: %v-th element segment: %v", common.ErrIndexOutOfRange, i, err)
+			}
+		}
+	}
+	for i, d := range m.SecData {
+		if d.Mode != types.SegmentModeActive {
+			continue
+		}
+		if _, err := m.Memory(d.MemIdx); err != nil {
+			return fmt.Errorf("%w: %v-th data segment: %v", common.ErrIndexOutOfRange, i, err)
+		}
+	}
+	return nil
+}
+
+// Bytes returns the module binary of the module built so far, or the first error met
+func (b *Builder) Bytes() ([]byte, error) {
+	m, err := b.Module()
+	if err != nil {
+		return nil, err
+	}
+	return m.Encode()
+}
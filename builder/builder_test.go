@@ -0,0 +1,98 @@
+package builder
+
+import (
+	"errors"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/decode"
+	"github.com/LBruyne/wasm-decode/operator"
+	"github.com/LBruyne/wasm-decode/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	i32 := types.ValueTypeI32
+	b := New()
+	log := b.AddType([]types.ValueType{i32}, nil)
+	add := b.AddType([]types.ValueType{i32, i32}, []types.ValueType{i32})
+	assert.Equal(t, log, b.AddType([]types.ValueType{i32}, nil))
+
+	assert.Equal(t, uint32(0), b.ImportFunc("env", "log", log))
+	assert.Equal(t, uint32(0), b.ImportGlobal("env", "base", i32, false))
+	body, err := operator.NewAssembler().LocalGet(0).LocalGet(1).I32Add().Assemble()
+	assert.Nil(t, err)
+	fn := b.AddFunction(add, []types.ValueType{i32, i32, types.ValueTypeI64}, body)
+	assert.Equal(t, uint32(1), fn)
+	mem := b.AddMemory(LimitsMax(1, 2))
+	table := b.AddTable(types.ElemTypeFuncRef, Limits(1))
+	g := b.AddGlobal(i32, true, types.NewI32Const(-1))
+	assert.Equal(t, uint32(1), g)
+	b.AddData(mem, 16, []byte("hello"))
+	b.AddPassiveData([]byte("world"))
+	b.AddElements(table, 0, fn)
+	b.ExportFunc("add", fn).ExportMemory("memory", mem).ExportGlobal("counter", g).AddCustom("meta", []byte{1})
+
+	buf, err := b.Bytes()
+	assert.Nil(t, err)
+	mod, err := decode.DecodeBytes(buf)
+	assert.Nil(t, err)
+
+	assert.Len(t, mod.SecType, 2)
+	exp, err := mod.ExportByName("add")
+	assert.Nil(t, err)
+	assert.Equal(t, fn, exp.Index)
+	assert.False(t, exp.Reexported())
+	assert.Equal(t, types.CodeSegmentBody(body), exp.Function.Code.Body)
+	if assert.Len(t, exp.Function.Code.Locals, 2) {
+		assert.Equal(t, uint32(2), exp.Function.Code.Locals[0].Count)
+		assert.Equal(t, types.ValueTypeI64, exp.Function.Code.Locals[1].Type)
+	}
+	sig, err := mod.FuncSignature(0)
+	assert.Nil(t, err)
+	assert.Equal(t, []types.ValueType{i32}, sig.InputType)
+
+	exp, err = mod.ExportByName("counter")
+	assert.Nil(t, err)
+	v, err := exp.Global.Init.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, int32(-1), v)
+	assert.Equal(t, []byte("hello"), mod.SecData[0].Init)
+	assert.Equal(t, uint32(2), *mod.SecDataCount)
+	assert.Equal(t, []uint32{fn}, mod.SecElement[0].Init)
+	assert.Equal(t, "meta", mod.SecCustom.Name)
+}
+
+func TestBuilderErrors(t *testing.T) {
+	b := New()
+	ft := b.AddType(nil, nil)
+	b.AddFunction(ft, nil, []byte{byte(operator.OpCodeEnd)})
+	b.ImportFunc("env", "f", ft)
+	_, err := b.Module()
+	assert.NotNil(t, err)
+
+	b = New()
+	b.ExportFunc("f", 0)
+	_, err = b.Bytes()
+	assert.True(t, errors.Is(err, common.ErrIndexOutOfRange))
+
+	b = New()
+	b.AddGlobal(types.ValueTypeI32, false, nil)
+	_, err = b.Bytes()
+	assert.Error(t, err)
+
+	b = New()
+	b.AddGlobal(types.ValueTypeI32, false, types.NewI64Const(1))
+	_, err = b.Bytes()
+	assert.Error(t, err)
+
+	b = New()
+	b.ImportGlobal("env", "g", types.ValueTypeI32, false)
+	b.AddGlobal(types.ValueTypeI32, false, types.NewGlobalGet(0))
+	_, err = b.Bytes()
+	assert.Nil(t, err)
+
+	// a module built by hand fails to encode instead of panicking
+	mod := &types.Module{SecGlobal: []*types.GlobalSegment{{Type: &types.GlobalType{Value: types.ValueTypeI32}}}}
+	_, err = mod.Encode()
+	assert.Error(t, err)
+}
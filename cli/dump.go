@@ -1,207 +1,489 @@
 package cli
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"github.com/LBruyne/wasm-decode/types"
+	"io"
+	"sort"
+	"strings"
 )
 
 type Dumper struct {
-	module              *types.Module
-	importedFuncCount   int
-	importedTableCount  int
-	importedMemCount    int
-	importedGlobalCount int
+	module    *types.Module
+	w         io.Writer
+	formatter Formatter
+	features  bool
 }
 
-func NewDumper(module *types.Module) *Dumper {
+// NewDumper returns a dumper of module writing to w in the text format
+func NewDumper(module *types.Module, w io.Writer) *Dumper {
 	return &Dumper{
-		module: module,
+		module:    module,
+		w:         w,
+		formatter: TextFormatter{},
 	}
 }
 
-func (d *Dumper) Dump() {
-	fmt.Printf("Version: 0x%02x\n", d.module.Version)
-	d.dumpTypeSection()
-	d.dumpImportSection()
-	d.dumpFuncSection()
-	d.dumpTableSection()
-	d.dumpMemSection()
-	d.dumpGlobalSection()
-	d.dumpExportSection()
-	d.dumpStartSection()
-	d.dumpElemSection()
-	d.dumpCodeSection()
-	d.dumpDataSection()
-	d.dumpCustomSection()
+// WithFormatter makes d render with f, e.g. JSONFormatter{}
+func (d *Dumper) WithFormatter(f Formatter) *Dumper {
+	d.formatter = f
+	return d
 }
 
-func (d *Dumper) dumpTypeSection() {
-	fmt.Printf("Type[%d]:\n", len(d.module.SecType))
+// WithFeatures makes Dump also write the features used by the module, as DumpFeatures does
+func (d *Dumper) WithFeatures() *Dumper {
+	d.features = true
+	return d
+}
+
+// version is the version field of the module binary, printed by the text format as its bytes
+// in hexadecimal, and by the structured ones as a number
+type version []byte
+
+func (v version) String() string {
+	return fmt.Sprintf("0x%02x", []byte(v))
+}
+
+func (v version) MarshalJSON() ([]byte, error) {
+	if len(v) != 4 {
+		return json.Marshal(v.String())
+	}
+	return json.Marshal(binary.LittleEndian.Uint32(v))
+}
+
+// Dump writes the content of each section
+func (d *Dumper) Dump() error {
+	doc, err := d.Document()
+	if err != nil {
+		return err
+	}
+	return d.formatter.Format(d.w, doc)
+}
+
+// Document returns what Dump writes, to be rendered by a Formatter
+func (d *Dumper) Document() (*Document, error) {
+	doc := &Document{
+		Fields: []Field{{"version", version(d.module.Version)}},
+		Sections: []*Section{
+			d.dumpTypeSection(),
+			d.dumpImportSection(),
+			d.dumpFuncSection(),
+			d.dumpTableSection(),
+			d.dumpMemSection(),
+			d.dumpGlobalSection(),
+			d.dumpExportSection(),
+			d.dumpStartSection(),
+			d.dumpElemSection(),
+			d.dumpCodeSection(),
+			d.dumpDataSection(),
+			d.dumpCustomSections(),
+		},
+	}
+	if s := d.dumpUnknownSections(); s != nil {
+		doc.Sections = append(doc.Sections, s)
+	}
+	if s := d.dumpDiagnostics(); s != nil {
+		doc.Sections = append(doc.Sections, s)
+	}
+	if d.features {
+		s, err := d.dumpFeatures()
+		if err != nil {
+			return nil, err
+		}
+		doc.Sections = append(doc.Sections, s)
+	}
+	return doc, nil
+}
+
+// DumpFeatures writes the post-MVP features used by the module and where each one is first required
+func (d *Dumper) DumpFeatures() error {
+	s, err := d.dumpFeatures()
+	if err != nil {
+		return err
+	}
+	return d.formatter.Format(d.w, &Document{Sections: []*Section{s}})
+}
+
+func (d *Dumper) dumpFeatures() (*Section, error) {
+	rp, err := d.module.DetectFeatures()
+	if err != nil {
+		return nil, fmt.Errorf("detect features: %w", err)
+	}
+
+	s := &Section{Name: "features", Counted: true, Summary: rp.Features.String()}
+	for _, u := range rp.Usages {
+		s.Entries = append(s.Entries, &Entry{
+			Lines: []string{u.String()},
+			Fields: []Field{
+				{"feature", u.Feature.String()},
+				{"section", u.Section.String()},
+				{"index", u.Index},
+				{"offset", u.Offset},
+				{"reason", u.Reason},
+			},
+		})
+	}
+	return s, nil
+}
+
+func (d *Dumper) dumpTypeSection() *Section {
+	s := &Section{Name: "type", Counted: true}
 	for i, ft := range d.module.SecType {
-		fmt.Printf("  type[%d]: ", i)
-		d.dumpFunctionType(ft)
-		fmt.Printf("\n")
+		s.Entries = append(s.Entries, &Entry{
+			Lines: []string{fmt.Sprintf("type[%d]: %s", i, functionTypeText(ft))},
+			Fields: []Field{
+				{"index", i},
+				{"params", valueTypeNames(ft.InputType)},
+				{"results", valueTypeNames(ft.ReturnType)},
+			},
+		})
 	}
+	return s
 }
 
-func (d *Dumper) dumpImportSection() {
-	fmt.Printf("Import[%d]:\n", len(d.module.SecImport))
+func (d *Dumper) dumpImportSection() *Section {
+	var funcIdx, tableIdx, memIdx, globalIdx int
+	s := &Section{Name: "import", Counted: true}
 	for _, imp := range d.module.SecImport {
-		switch imp.Desc.Kind {
-		case types.ImportTypeFunc:
-			fmt.Printf("  func[%d]: <%s.%s>, sig=%d\n",
-				d.importedFuncCount, imp.Module, imp.Name, imp.Desc.TypeIndex)
-			d.importedFuncCount++
-		case types.ImportTypeTable:
-			fmt.Printf("  table[%d]: <%s.%s>, %v\n",
-				d.importedTableCount, imp.Module, imp.Name, imp.Desc.TableType.Limit)
-			d.importedTableCount++
-		case types.ImportTypeMem:
-			fmt.Printf("  memory[%d]: <%s.%s>, %v\n",
-				d.importedMemCount, imp.Module, imp.Name, imp.Desc.MemType)
-			d.importedMemCount++
-		case types.ImportTypeGlobal:
-			fmt.Printf("  global[%d]: <%s.%s>, %v\n",
-				d.importedGlobalCount, imp.Module, imp.Name, imp.Desc.GlobalType)
-			d.importedGlobalCount++
+		module, name := types.SafeName(imp.Module), types.SafeName(imp.Name)
+		var e *Entry
+		switch desc := imp.Desc.(type) {
+		case *types.FuncImport:
+			e = &Entry{
+				Lines:  []string{fmt.Sprintf("func[%d]: <%s.%s>, sig=%d", funcIdx, module, name, desc.TypeIndex)},
+				Fields: []Field{{"index", funcIdx}, {"sig", desc.TypeIndex}},
+			}
+			funcIdx++
+		case *types.TableImport:
+			e = &Entry{
+				Lines:  []string{fmt.Sprintf("table[%d]: <%s.%s>, %v", tableIdx, module, name, desc.Type.Limit)},
+				Fields: append([]Field{{"index", tableIdx}, {"type", desc.Type.ElemType.String()}}, limitFields(desc.Type.Limit)...),
+			}
+			tableIdx++
+		case *types.MemoryImport:
+			e = &Entry{
+				Lines:  []string{fmt.Sprintf("memory[%d]: <%s.%s>, %v", memIdx, module, name, desc.Type)},
+				Fields: append([]Field{{"index", memIdx}}, limitFields(desc.Type)...),
+			}
+			memIdx++
+		case *types.GlobalImport:
+			e = &Entry{
+				Lines:  []string{fmt.Sprintf("global[%d]: <%s.%s>, %v", globalIdx, module, name, desc.Type)},
+				Fields: []Field{{"index", globalIdx}, {"type", desc.Type.Value.String()}, {"mutable", desc.Type.Mutable}},
+			}
+			globalIdx++
+		default:
+			continue
 		}
+		e.Fields = append([]Field{{"kind", imp.Desc.Kind().String()}, {"module", module}, {"name", name}}, e.Fields...)
+		s.Entries = append(s.Entries, e)
 	}
-	return
+	return s
 }
 
-func (d *Dumper) dumpFuncSection() {
-	fmt.Printf("Function[%d]:\n", len(d.module.SecFunction))
+func (d *Dumper) dumpFuncSection() *Section {
+	s := &Section{Name: "function", Counted: true}
+	base := int(d.module.ImportedCount(types.ExternalKindFunc))
 	for i, sig := range d.module.SecFunction {
-		fmt.Printf("  func[%d]: sig=%d\n",
-			d.importedFuncCount+i, sig)
+		s.Entries = append(s.Entries, &Entry{
+			Lines:  []string{fmt.Sprintf("func[%d]: sig=%d", base+i, sig)},
+			Fields: []Field{{"index", base + i}, {"sig", sig}},
+		})
 	}
+	return s
 }
 
-func (d *Dumper) dumpTableSection() {
-	fmt.Printf("Table[%d]:\n", len(d.module.SecTable))
+func (d *Dumper) dumpTableSection() *Section {
+	s := &Section{Name: "table", Counted: true}
+	base := int(d.module.ImportedCount(types.ExternalKindTable))
 	for i, t := range d.module.SecTable {
-		fmt.Printf("  table[%d]: ", d.importedTableCount+i)
-		d.dumpElemType(t.ElemType)
-		fmt.Printf(" ")
-		d.dumpLimitType(t.Limit)
-		fmt.Printf("\n")
+		s.Entries = append(s.Entries, &Entry{
+			Lines:  []string{fmt.Sprintf("table[%d]: %s %s", base+i, elemTypeText(t.ElemType), limitText(t.Limit))},
+			Fields: append([]Field{{"index", base + i}, {"type", t.ElemType.String()}}, limitFields(t.Limit)...),
+		})
 	}
+	return s
 }
 
-func (d *Dumper) dumpMemSection() {
-	fmt.Printf("Memory[%d]:\n", len(d.module.SecMemory))
+func (d *Dumper) dumpMemSection() *Section {
+	s := &Section{Name: "memory", Counted: true}
+	base := int(d.module.ImportedCount(types.ExternalKindMemory))
 	for i, l := range d.module.SecMemory {
-		fmt.Printf("  memory[%d]: pages ", d.importedMemCount+i)
-		d.dumpLimitType(l)
-		fmt.Printf("\n")
+		s.Entries = append(s.Entries, &Entry{
+			Lines:  []string{fmt.Sprintf("memory[%d]: pages %s", base+i, limitText(l))},
+			Fields: append([]Field{{"index", base + i}}, limitFields(l)...),
+		})
 	}
+	return s
 }
 
-func (d *Dumper) dumpGlobalSection() {
-	fmt.Printf("Global[%d]:\n", len(d.module.SecGlobal))
+func (d *Dumper) dumpGlobalSection() *Section {
+	s := &Section{Name: "global", Counted: true}
+	base := int(d.module.ImportedCount(types.ExternalKindGlobal))
 	for i, g := range d.module.SecGlobal {
-		fmt.Printf("  global[%d]: ", d.importedGlobalCount+i)
-		dumpGlobalType(g.Type)
-		fmt.Printf(" - ")
-		// dumpInitExpression(g.Init)
-		fmt.Printf("\n")
+		s.Entries = append(s.Entries, &Entry{
+			Lines: []string{fmt.Sprintf("global[%d]: %v mutable=%v - init %v", base+i, g.Type.Value, g.Type.Mutable, g.Init)},
+			Fields: []Field{
+				{"index", base + i},
+				{"type", g.Type.Value.String()},
+				{"mutable", g.Type.Mutable},
+				{"init", fmt.Sprint(g.Init)},
+			},
+		})
 	}
+	return s
 }
 
-func dumpGlobalType(gt *types.GlobalType) {
-	fmt.Printf("%v", gt.Value.Type)
+func (d *Dumper) dumpExportSection() *Section {
+	s := &Section{Name: "export", Counted: true}
+	for _, exp := range d.module.SecExport {
+		name := types.SafeName(exp.Name)
+		s.Entries = append(s.Entries, &Entry{
+			Lines:  []string{fmt.Sprintf("%v[%d]: name=<%s>", exp.Desc.Kind, exp.Desc.Index, name)},
+			Fields: []Field{{"kind", exp.Desc.Kind.String()}, {"index", exp.Desc.Index}, {"name", name}},
+		})
+	}
+	return s
+}
 
-	fmt.Printf(" ")
+func (d *Dumper) dumpStartSection() *Section {
+	s := &Section{Name: "start", Empty: "No start function."}
+	if d.module.SecStart != nil {
+		s.Entries = append(s.Entries, &Entry{
+			Lines:  []string{fmt.Sprintf("func=%d", *d.module.SecStart)},
+			Fields: []Field{{"func", *d.module.SecStart}},
+		})
+	}
+	return s
+}
 
-	if gt.Mutable {
-		fmt.Printf("mutable=true")
-	} else {
-		fmt.Printf("mutable=false")
+func (d *Dumper) dumpElemSection() *Section {
+	s := &Section{Name: "element", Counted: true}
+	for i, elem := range d.module.SecElement {
+		s.Entries = append(s.Entries, &Entry{
+			Lines:  []string{fmt.Sprintf("elem[%d]: table=%d", i, elem.TableIdx)},
+			Fields: []Field{{"index", i}, {"table", elem.TableIdx}},
+		})
 	}
+	return s
 }
 
-func (d *Dumper) dumpExportSection() {
-	fmt.Printf("Export[%d]:\n", len(d.module.SecExport))
-	for _, exp := range d.module.SecExport {
-		switch exp.Desc.Kind {
-		case types.ExportTypeFunc:
-			fmt.Printf("  func[%d]: name=<%s>\n", int(exp.Desc.Index), exp.Name)
-		case types.ExportTypeTable:
-			fmt.Printf("  table[%d]: name=<%s>\n", int(exp.Desc.Index), exp.Name)
-		case types.ExportTypeMem:
-			fmt.Printf("  memory[%d]: name=<%s>\n", int(exp.Desc.Index), exp.Name)
-		case types.ExportTypeGlobal:
-			fmt.Printf("  global[%d]: name=<%s>\n", int(exp.Desc.Index), exp.Name)
+func (d *Dumper) dumpCodeSection() *Section {
+	s := &Section{Name: "code", Counted: true}
+	base := int(d.module.ImportedCount(types.ExternalKindFunc))
+	for i, c := range d.module.SecCode {
+		e := &Entry{
+			Lines:  []string{fmt.Sprintf("func[%d]:", base+i)},
+			Fields: []Field{{"index", base + i}},
 		}
+		if c != nil {
+			e.Fields = append(e.Fields, Field{"size", c.BodySize})
+		}
+		s.Entries = append(s.Entries, e)
 	}
+	return s
 }
 
-func (d *Dumper) dumpStartSection() {
-	fmt.Printf("Start:\n")
-	if d.module.SecStart != nil {
-		fmt.Printf("  func=%d\n", d.module.SecStart.(uint32))
-	} else {
-		fmt.Printf("  No start function.\n")
+func (d *Dumper) dumpDataSection() *Section {
+	s := &Section{Name: "data", Counted: true}
+	for i, data := range d.module.SecData {
+		s.Entries = append(s.Entries, &Entry{
+			Lines:  []string{fmt.Sprintf("data[%d]: mem=%d", i, data.MemIdx)},
+			Fields: []Field{{"index", i}, {"memory", data.MemIdx}, {"size", data.InitSize}},
+		})
 	}
+	return s
 }
 
-func (d *Dumper) dumpElemSection() {
-	fmt.Printf("Element[%d]:\n", len(d.module.SecElement))
-	for i, elem := range d.module.SecElement {
-		fmt.Printf("  elem[%d]: table=%d\n", i, elem.TableIdx)
+func (d *Dumper) dumpCustomSections() *Section {
+	s := &Section{Name: "custom", Counted: true}
+	for i, c := range d.module.SecCustoms {
+		name := types.SafeName(c.Name)
+		e := &Entry{
+			Lines:  []string{fmt.Sprintf("custom[%d]: name=<%s> size=%d", i, name, len(c.Bytes))},
+			Fields: []Field{{"index", i}, {"name", name}, {"size", len(c.Bytes)}},
+		}
+		if c.Err != nil {
+			msg := types.SafeName(c.Err.Error())
+			e.Lines = append(e.Lines, "  error="+msg)
+			e.Fields = append(e.Fields, Field{"error", msg})
+		}
+		if c.Value == nil {
+			data := types.SafeName(string(c.Bytes))
+			e.Lines = append(e.Lines, "  "+data)
+			e.Fields = append(e.Fields, Field{"data", data})
+		} else {
+			for _, f := range customValueFields(c.Value) {
+				e.Fields = append(e.Fields, f)
+				switch v := f.Value.(type) {
+				case []string:
+					for _, l := range v {
+						e.Lines = append(e.Lines, fmt.Sprintf("  %s: %s", f.Key, l))
+					}
+				default:
+					e.Lines = append(e.Lines, fmt.Sprintf("  %s: %v", f.Key, v))
+				}
+			}
+		}
+		s.Entries = append(s.Entries, e)
 	}
+	return s
 }
 
-func (d *Dumper) dumpCodeSection() {
-	fmt.Printf("Code[%d]:\n", len(d.module.SecCode))
-	for i, _ := range d.module.SecCode {
-		fmt.Printf("  func[%d]:\n", d.importedFuncCount+i)
+// customValueFields renders the value decoded from a known custom section, with every name escaped
+func customValueFields(value interface{}) []Field {
+	switch v := value.(type) {
+	case *types.NameSection:
+		var fields []Field
+		if v.Module != "" {
+			fields = append(fields, Field{"module", types.SafeName(v.Module)})
+		}
+		if len(v.Functions) > 0 {
+			var funcs []string
+			for _, idx := range sortedIndices(v.Functions) {
+				funcs = append(funcs, fmt.Sprintf("func[%d] <%s>", idx, types.SafeName(v.Functions[idx])))
+			}
+			fields = append(fields, Field{"functions", funcs})
+		}
+		if len(v.Locals) > 0 {
+			fidxs := make([]uint32, 0, len(v.Locals))
+			for fidx := range v.Locals {
+				fidxs = append(fidxs, fidx)
+			}
+			sort.Slice(fidxs, func(i, j int) bool { return fidxs[i] < fidxs[j] })
+			var locals []string
+			for _, fidx := range fidxs {
+				names := v.Locals[fidx]
+				for _, idx := range sortedIndices(names) {
+					locals = append(locals, fmt.Sprintf("func[%d] local[%d] <%s>", fidx, idx, types.SafeName(names[idx])))
+				}
+			}
+			fields = append(fields, Field{"locals", locals})
+		}
+		return fields
+	case *types.ProducersSection:
+		var producers []string
+		for _, f := range v.Fields {
+			tools := make([]string, 0, len(f.Values))
+			for _, pv := range f.Values {
+				tool := types.SafeName(pv.Name)
+				if pv.Version != "" {
+					tool += " " + types.SafeName(pv.Version)
+				}
+				tools = append(tools, tool)
+			}
+			producers = append(producers, types.SafeName(f.Name)+"="+strings.Join(tools, ", "))
+		}
+		return []Field{{"producers", producers}}
+	case *types.TargetFeaturesSection:
+		features := make([]string, 0, len(v.Features))
+		for _, f := range v.Features {
+			features = append(features, string(f.Prefix)+types.SafeName(f.Name))
+		}
+		return []Field{{"features", features}}
+	default:
+		return []Field{{"value", types.SafeName(fmt.Sprintf("%v", v))}}
 	}
 }
 
-func (d *Dumper) dumpDataSection() {
-	fmt.Printf("Data[%d]:\n", len(d.module.SecData))
-	for i, data := range d.module.SecData {
-		fmt.Printf("  data[%d]: mem=%d\n", i, data.MemIdx)
+func sortedIndices(m types.NameMap) []uint32 {
+	idxs := make([]uint32, 0, len(m))
+	for idx := range m {
+		idxs = append(idxs, idx)
 	}
+	sort.Slice(idxs, func(i, j int) bool { return idxs[i] < idxs[j] })
+	return idxs
 }
 
-func (d *Dumper) dumpCustomSection() {
-	fmt.Printf("Custom:\n")
-	fmt.Printf("  name=%s\n", d.module.SecCustom.Name)
-	fmt.Printf("  %s\n", string(d.module.SecCustom.Bytes))
+func (d *Dumper) dumpUnknownSections() *Section {
+	if len(d.module.UnknownSections) == 0 {
+		return nil
+	}
+	s := &Section{Name: "unknown", Counted: true}
+	for _, u := range d.module.UnknownSections {
+		s.Entries = append(s.Entries, &Entry{
+			Lines:  []string{fmt.Sprintf("id=%d offset=0x%x size=%d", u.ID, u.Offset, u.Size)},
+			Fields: []Field{{"id", u.ID}, {"offset", u.Offset}, {"size", u.Size}},
+		})
+	}
+	return s
 }
 
-func (d *Dumper) dumpElemType(et byte) {
-	if et == types.ElemTypeFuncRef {
-		fmt.Printf("type=funcref")
+func (d *Dumper) dumpDiagnostics() *Section {
+	if len(d.module.Diagnostics) == 0 {
+		return nil
+	}
+	s := &Section{Name: "diagnostics", Counted: true}
+	for _, diag := range d.module.Diagnostics {
+		s.Entries = append(s.Entries, &Entry{
+			Lines: []string{types.SafeName(diag.Error())},
+			Fields: []Field{
+				{"section", diag.Section.String()},
+				{"offset", diag.Offset},
+				{"error", types.SafeName(diag.Err.Error())},
+			},
+		})
 	}
+	return s
 }
 
-func (d *Dumper) dumpLimitType(limit *types.LimitType) {
-	if limit.Tag == types.LimitTypeOnlyMin {
-		fmt.Printf("initial=%v", limit.Min)
-	} else if limit.Tag == types.LimitTypeBothMinAndMax {
-		fmt.Printf("initial=%v max=%v", limit.Min, limit.Max)
+func elemTypeText(et types.ValueType) string {
+	switch et {
+	case types.ElemTypeFuncRef:
+		return "type=funcref"
+	case types.ElemTypeExternRef:
+		return "type=externref"
 	}
+	return ""
 }
 
-func (d *Dumper) dumpFunctionType(ft *types.FunctionType) {
-	d.dumpValueTypes(ft.InputType)
-	fmt.Printf(" -> ")
-	d.dumpValueTypes(ft.ReturnType)
+func limitText(limit *types.LimitType) string {
+	var sb strings.Builder
+	if limit.HasMax() {
+		fmt.Fprintf(&sb, "initial=%v max=%v", limit.Min, limit.Max)
+	} else {
+		fmt.Fprintf(&sb, "initial=%v", limit.Min)
+	}
+	if limit.Shared() {
+		sb.WriteString(" shared")
+	}
+	if limit.Is64() {
+		sb.WriteString(" i64")
+	}
+	return sb.String()
 }
 
-func (d *Dumper) dumpValueTypes(vt []types.ValueType) {
-	// output
+func limitFields(limit *types.LimitType) []Field {
+	fields := []Field{{"initial", limit.Min}}
+	if limit.HasMax() {
+		fields = append(fields, Field{"max", limit.Max})
+	}
+	if limit.Shared() {
+		fields = append(fields, Field{"shared", true})
+	}
+	if limit.Is64() {
+		fields = append(fields, Field{"i64", true})
+	}
+	return fields
+}
+
+func functionTypeText(ft *types.FunctionType) string {
+	return valueTypesText(ft.InputType) + " -> " + valueTypesText(ft.ReturnType)
+}
+
+func valueTypesText(vt []types.ValueType) string {
 	if len(vt) == 0 {
-		fmt.Printf("nil")
-	} else {
-		for i, op := range vt {
-			fmt.Printf("%v", op.Type)
-			if i != len(vt)-1 {
-				fmt.Printf(", ")
-			}
-		}
+		return "nil"
+	}
+	return strings.Join(valueTypeNames(vt), ", ")
+}
+
+func valueTypeNames(vt []types.ValueType) []string {
+	names := make([]string, len(vt))
+	for i, t := range vt {
+		names[i] = t.String()
 	}
+	return names
 }
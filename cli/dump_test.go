@@ -1,20 +1,141 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"github.com/LBruyne/wasm-decode/builder"
 	"github.com/LBruyne/wasm-decode/decode"
+	"github.com/LBruyne/wasm-decode/types"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
 var (
 	fileName = "../examples/wasm/test.wasm"
+
+	update = flag.Bool("update", false, "rewrite the golden files of testdata")
 )
 
+// dump returns what Dump and DumpFeatures write for the module of fn in format
+func dump(t *testing.T, fn, format string) []byte {
+	mod, err := decode.DecodeFile(fn)
+	assert.Nil(t, err)
+	f, err := FormatterByName(format)
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	d := NewDumper(mod, &buf).WithFormatter(f)
+	assert.Nil(t, d.Dump())
+	assert.Nil(t, d.DumpFeatures())
+	return buf.Bytes()
+}
+
+func TestDumpGolden(t *testing.T) {
+	for _, name := range []string{"test", "fib"} {
+		for _, format := range []string{"text", "json", "yaml"} {
+			got := dump(t, "../examples/wasm/"+name+".wasm", format)
+			golden := filepath.Join("testdata", name+"."+format)
+			if *update {
+				assert.Nil(t, ioutil.WriteFile(golden, got, 0644))
+			}
+			want, err := ioutil.ReadFile(golden)
+			assert.Nil(t, err)
+			assert.Equal(t, string(want), string(got), golden)
+		}
+	}
+}
+
+func TestDumpJSON(t *testing.T) {
+	// Dump and DumpFeatures write a JSON object each
+	dec := json.NewDecoder(bytes.NewReader(dump(t, fileName, "json")))
+	var doc struct {
+		Version  uint32
+		Sections []struct {
+			Name    string
+			Entries []map[string]interface{}
+		}
+	}
+	assert.Nil(t, dec.Decode(&doc))
+	assert.EqualValues(t, 1, doc.Version)
+	assert.Equal(t, "type", doc.Sections[0].Name)
+	assert.Len(t, doc.Sections[0].Entries, 5)
+	assert.Nil(t, dec.Decode(&doc))
+	assert.Equal(t, "features", doc.Sections[0].Name)
+	assert.False(t, dec.More())
+}
+
+func TestDumpFeatures(t *testing.T) {
+	mod, err := decode.DecodeFile(fileName)
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	assert.Nil(t, NewDumper(mod, &buf).DumpFeatures())
+	assert.True(t, strings.HasPrefix(buf.String(), "Features["))
+}
+
+func TestDumpUnsafeNames(t *testing.T) {
+	b := builder.New()
+	b.ImportGlobal("\x1b[2J", "g", types.ValueTypeI32, true)
+	b.ExportGlobal("\x1b[2J", b.AddGlobal(types.ValueTypeI32, true, types.NewI32Const(0)))
+	b.AddCustom("\x1b[2J", []byte("\x1b[2J"))
+	b.AddCustom("producers", append([]byte("\x01\x08language\x01\x04"), "\x1b[2J\x011"...))
+	bs, err := b.Bytes()
+	assert.Nil(t, err)
+	mod, err := decode.DecodeBytes(bs)
+	assert.Nil(t, err)
+
+	for _, format := range []string{"text", "json", "yaml"} {
+		f, err := FormatterByName(format)
+		assert.Nil(t, err)
+		var buf bytes.Buffer
+		assert.Nil(t, NewDumper(mod, &buf).WithFormatter(f).WithFeatures().Dump())
+		assert.NotContains(t, buf.String(), "\x1b", format)
+		if format == "text" {
+			assert.Contains(t, buf.String(), `import of mutable global \u001b[2J.g`)
+			assert.Contains(t, buf.String(), `custom[0]: name=<\u001b[2J> size=4`)
+			assert.Contains(t, buf.String(), `producers: language=\u001b[2J 1`)
+		}
+	}
+
+	// the export of a mutable global is reported once the import is not
+	mod.SecImport = nil
+	mod.SecExport[0].Desc.Index = 0
+	var buf bytes.Buffer
+	assert.Nil(t, NewDumper(mod, &buf).DumpFeatures())
+	assert.Contains(t, buf.String(), `export of mutable global \u001b[2J`)
+	assert.NotContains(t, buf.String(), "\x1b")
+}
+
 func TestDump(t *testing.T) {
 	mod, err := decode.DecodeFile(fileName)
 	assert.Nil(t, err)
 	assert.NotNil(t, mod)
 
-	d := NewDumper(mod)
-	d.Dump()
+	var buf bytes.Buffer
+	assert.Nil(t, NewDumper(mod, &buf).Dump())
+	assert.True(t, strings.HasPrefix(buf.String(), "Version: 0x01000000\nType[5]:\n"))
+}
+
+func TestDumpTwice(t *testing.T) {
+	mod, err := decode.DecodeFile(fileName)
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	d := NewDumper(mod, &buf)
+	assert.Nil(t, d.Dump())
+	first := buf.String()
+	assert.NotEmpty(t, first)
+
+	buf.Reset()
+	assert.Nil(t, d.Dump())
+	assert.Equal(t, first, buf.String())
+}
+
+func TestFormatterByName(t *testing.T) {
+	_, err := FormatterByName("xml")
+	assert.NotNil(t, err)
 }
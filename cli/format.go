@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Document is what a Dumper renders, a list of sections. The text lines of the entries
+// are the layout of the text format, the fields are what the structured formats show.
+type Document struct {
+	Fields   []Field // values of the module, e.g. its version
+	Sections []*Section
+}
+
+// Section is a titled list of entries
+type Section struct {
+	Name    string // lower case name, e.g. "type"
+	Counted bool   // the number of entries follows the name in the text format
+	Summary string // text following the name in the text format, and a field of the structured formats
+	Empty   string // line of the text format if there is no entry
+	Entries []*Entry
+}
+
+// Entry is an item of a section
+type Entry struct {
+	Lines  []string // lines of the text format
+	Fields []Field
+}
+
+// Field is a named value, a string, a bool, a number or a slice of strings
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Formatter renders documents to a writer
+type Formatter interface {
+	Format(w io.Writer, doc *Document) error
+}
+
+var formatters = map[string]Formatter{
+	"text": TextFormatter{},
+	"json": JSONFormatter{},
+	"yaml": YAMLFormatter{},
+}
+
+// FormatterByName returns the formatter of name: "text", "json" or "yaml"
+func FormatterByName(name string) (Formatter, error) {
+	if f, ok := formatters[name]; ok {
+		return f, nil
+	}
+	return nil, fmt.Errorf("unknown format: %s", name)
+}
+
+// title returns s with its first letter in upper case
+func title(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// TextFormatter renders the human readable layout, one line per entry
+type TextFormatter struct{}
+
+func (TextFormatter) Format(w io.Writer, doc *Document) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range doc.Fields {
+		fmt.Fprintf(bw, "%s: %v\n", title(f.Key), f.Value)
+	}
+	for _, s := range doc.Sections {
+		bw.WriteString(title(s.Name))
+		if s.Counted {
+			fmt.Fprintf(bw, "[%d]", len(s.Entries))
+		}
+		bw.WriteString(":")
+		if s.Summary != "" {
+			bw.WriteString(" " + s.Summary)
+		}
+		bw.WriteString("\n")
+
+		if len(s.Entries) == 0 && s.Empty != "" {
+			bw.WriteString("  " + s.Empty + "\n")
+		}
+		for _, e := range s.Entries {
+			for _, l := range e.Lines {
+				bw.WriteString("  " + l + "\n")
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// sectionFields returns the fields of the structured formats of s, but its entries
+func sectionFields(s *Section) []Field {
+	fields := []Field{{"name", s.Name}}
+	if s.Summary != "" {
+		fields = append(fields, Field{"summary", s.Summary})
+	}
+	return fields
+}
+
+// JSONFormatter renders an indented JSON object per document, whose fields keep their order
+type JSONFormatter struct{}
+
+func (JSONFormatter) Format(w io.Writer, doc *Document) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("{")
+	for i, f := range doc.Fields {
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n  ")
+		if err := writeJSONField(bw, f); err != nil {
+			return err
+		}
+	}
+	if len(doc.Fields) > 0 {
+		bw.WriteString(",")
+	}
+	bw.WriteString("\n  \"sections\": [")
+	for i, s := range doc.Sections {
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n    {")
+		for _, f := range sectionFields(s) {
+			if err := writeJSONField(bw, f); err != nil {
+				return err
+			}
+			bw.WriteString(", ")
+		}
+		bw.WriteString("\"entries\": [")
+		for j, e := range s.Entries {
+			if j > 0 {
+				bw.WriteString(",")
+			}
+			bw.WriteString("\n      {")
+			for k, f := range e.Fields {
+				if k > 0 {
+					bw.WriteString(", ")
+				}
+				if err := writeJSONField(bw, f); err != nil {
+					return err
+				}
+			}
+			bw.WriteString("}")
+		}
+		if len(s.Entries) > 0 {
+			bw.WriteString("\n    ")
+		}
+		bw.WriteString("]}")
+	}
+	if len(doc.Sections) > 0 {
+		bw.WriteString("\n  ")
+	}
+	bw.WriteString("]\n}\n")
+	return bw.Flush()
+}
+
+// marshal returns v in JSON, without escaping the HTML characters such as the brackets of names
+func marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func writeJSONField(w io.Writer, f Field) error {
+	k, err := marshal(f.Key)
+	if err != nil {
+		return err
+	}
+	v, err := marshal(f.Value)
+	if err != nil {
+		return fmt.Errorf("field %s: %w", f.Key, err)
+	}
+	_, err = fmt.Fprintf(w, "%s: %s", k, v)
+	return err
+}
+
+// YAMLFormatter renders a YAML document per document, the values are in the flow style of JSON,
+// which YAML accepts
+type YAMLFormatter struct{}
+
+func (YAMLFormatter) Format(w io.Writer, doc *Document) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("---\n")
+	for _, f := range doc.Fields {
+		if err := writeYAMLField(bw, "", f); err != nil {
+			return err
+		}
+	}
+	if len(doc.Sections) == 0 {
+		bw.WriteString("sections: []\n")
+	} else {
+		bw.WriteString("sections:\n")
+	}
+	for _, s := range doc.Sections {
+		for i, f := range sectionFields(s) {
+			indent := "    "
+			if i == 0 {
+				indent = "  - "
+			}
+			if err := writeYAMLField(bw, indent, f); err != nil {
+				return err
+			}
+		}
+		if len(s.Entries) == 0 {
+			bw.WriteString("    entries: []\n")
+			continue
+		}
+		bw.WriteString("    entries:\n")
+		for _, e := range s.Entries {
+			if len(e.Fields) == 0 {
+				bw.WriteString("      - {}\n")
+			}
+			for i, f := range e.Fields {
+				indent := "        "
+				if i == 0 {
+					indent = "      - "
+				}
+				if err := writeYAMLField(bw, indent, f); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+func writeYAMLField(w io.Writer, indent string, f Field) error {
+	v, err := marshal(f.Value)
+	if err != nil {
+		return fmt.Errorf("field %s: %w", f.Key, err)
+	}
+	_, err = fmt.Fprintf(w, "%s%s: %s\n", indent, f.Key, v)
+	return err
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/LBruyne/wasm-decode/cli"
+	"github.com/LBruyne/wasm-decode/operator"
+	"github.com/LBruyne/wasm-decode/types"
+	"io"
+	"math"
+	"strings"
+)
+
+var commands = []command{
+	{name: "dump", summary: "print the content of each section", setup: setupDump},
+	{name: "validate", summary: "check that the modules decode and their references resolve", setup: setupValidate},
+	{name: "sections", summary: "list the sections with their offsets and sizes", setup: setupSections},
+	{name: "imports", summary: "list the imports", setup: setupImports},
+	{name: "exports", summary: "list the exports", setup: setupExports},
+	{name: "disasm", summary: "print the instructions of the function bodies", setup: setupDisasm},
+	{name: "custom", summary: "list the custom sections or write the bytes of one", setup: setupCustom},
+}
+
+// setupDump writes a document per module, for the structured formats the documents name their file,
+// and they are put in an array by JSON when there are several files
+func setupDump(fs *flag.FlagSet) *runner {
+	detect := fs.Bool("detect", false, "also print the post-MVP features used by the module")
+	format := fs.String("format", "text", `output format: "text", "json" or "yaml"`)
+
+	var f cli.Formatter
+	opened := false
+	return &runner{
+		check: func() (err error) {
+			f, err = cli.FormatterByName(*format)
+			return err
+		},
+		run: func(out io.Writer, file string, mod *types.Module) error {
+			d := cli.NewDumper(mod, out).WithFormatter(f)
+			if *detect {
+				d.WithFeatures()
+			}
+			doc, err := d.Document()
+			if err != nil {
+				return err
+			}
+			if *format != "text" {
+				doc.Fields = append([]cli.Field{{Key: "file", Value: file}}, doc.Fields...)
+			}
+			return f.Format(out, doc)
+		},
+		separate: func(out io.Writer, file string, first bool) {
+			switch *format {
+			case "text":
+				header(out, file, first)
+			case "json":
+				if first {
+					fmt.Fprint(out, "[\n")
+					opened = true
+				} else {
+					fmt.Fprint(out, ",\n")
+				}
+			}
+		},
+		end: func(out io.Writer) {
+			if *format == "json" {
+				if !opened {
+					fmt.Fprint(out, "[")
+				}
+				fmt.Fprint(out, "]\n")
+			}
+		},
+	}
+}
+
+func setupValidate(fs *flag.FlagSet) *runner {
+	return &runner{
+		run: func(out io.Writer, file string, mod *types.Module) error {
+			if err := validate(mod); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%s: ok\n", file)
+			return nil
+		},
+		// the output names the files
+		separate: func(io.Writer, string, bool) {},
+	}
+}
+
+// validate checks what the decoding leaves unchecked: the instructions of the bodies,
+// and the indices referred to by the other sections
+func validate(mod *types.Module) error {
+	if len(mod.Diagnostics) != 0 {
+		return mod.Diagnostics[0]
+	}
+	if err := types.Walk(mod, types.BaseVisitor{}); err != nil {
+		return err
+	}
+
+	for _, f := range mod.Functions() {
+		if f.Type == nil {
+			return fmt.Errorf("func[%d]: type %d of %d", f.Index, f.TypeIndex, len(mod.SecType))
+		}
+		if !f.Imported() && f.Code == nil {
+			return fmt.Errorf("func[%d]: missing body", f.Index)
+		}
+	}
+	if len(mod.SecCode) > len(mod.SecFunction) {
+		return fmt.Errorf("%d bodies of %d functions", len(mod.SecCode), len(mod.SecFunction))
+	}
+	names := make(map[string]bool, len(mod.SecExport))
+	for i, exp := range mod.SecExport {
+		if names[exp.Name] {
+			return fmt.Errorf("export[%d]: duplicate name %q", i, types.SafeName(exp.Name))
+		}
+		names[exp.Name] = true
+		if err := resolveExport(mod, exp.Desc); err != nil {
+			return fmt.Errorf("export[%d] %q: %w", i, types.SafeName(exp.Name), err)
+		}
+	}
+	if mod.SecStart != nil {
+		if _, err := mod.Function(*mod.SecStart); err != nil {
+			return fmt.Errorf("start: %w", err)
+		}
+	}
+	for i, e := range mod.SecElement {
+		if e.Mode == types.SegmentModeActive {
+			if _, err := mod.Table(e.TableIdx); err != nil {
+				return fmt.Errorf("elem[%d]: %w", i, err)
+			}
+		}
+		for _, idx := range e.Init {
+			if _, err := mod.Function(idx); err != nil {
+				return fmt.Errorf("elem[%d]: %w", i, err)
+			}
+		}
+	}
+	for i, d := range mod.SecData {
+		if d.Mode == types.SegmentModeActive {
+			if _, err := mod.Memory(d.MemIdx); err != nil {
+				return fmt.Errorf("data[%d]: %w", i, err)
+			}
+		}
+	}
+	for _, c := range mod.SecCustoms {
+		if c.Err != nil {
+			return fmt.Errorf("custom section %q: %w", c.Name, c.Err)
+		}
+	}
+	return nil
+}
+
+// resolveExport checks that the index of desc is in the index space of its kind
+func resolveExport(mod *types.Module, desc *types.ExportDescription) error {
+	var err error
+	switch desc.Kind {
+	case types.ExternalKindFunc:
+		_, err = mod.Function(desc.Index)
+	case types.ExternalKindTable:
+		_, err = mod.Table(desc.Index)
+	case types.ExternalKindMemory:
+		_, err = mod.Memory(desc.Index)
+	case types.ExternalKindGlobal:
+		_, err = mod.Global(desc.Index)
+	default:
+		err = fmt.Errorf("unknown export kind %v", desc.Kind)
+	}
+	return err
+}
+
+func setupSections(fs *flag.FlagSet) *runner {
+	return &runner{run: func(out io.Writer, file string, mod *types.Module) error {
+		customs := 0
+		for _, s := range mod.Sections {
+			fmt.Fprintf(out, "%9s start=%#08x end=%#08x size=%d", s.ID, s.Offset, s.PayloadOffset+int64(s.Size), s.Size)
+			if s.ID == types.SectionIDCustom && customs < len(mod.SecCustoms) {
+				fmt.Fprintf(out, " %q", types.SafeName(mod.SecCustoms[customs].Name))
+				customs++
+			}
+			fmt.Fprintln(out)
+		}
+		return nil
+	}}
+}
+
+func setupImports(fs *flag.FlagSet) *runner {
+	return &runner{run: func(out io.Writer, file string, mod *types.Module) error {
+		var counts [4]uint32
+		for _, imp := range mod.SecImport {
+			kind := imp.Desc.Kind()
+			var idx uint32
+			if int(kind) < len(counts) {
+				idx = counts[kind]
+				counts[kind]++
+			}
+
+			fmt.Fprintf(out, "%s[%d] <%s.%s>", kind, idx, types.SafeName(imp.Module), types.SafeName(imp.Name))
+			switch desc := imp.Desc.(type) {
+			case *types.FuncImport:
+				fmt.Fprintf(out, " sig=%d\n", desc.TypeIndex)
+			case *types.TableImport:
+				fmt.Fprintf(out, " %v\n", desc.Type.Limit)
+			case *types.MemoryImport:
+				fmt.Fprintf(out, " %v\n", desc.Type)
+			case *types.GlobalImport:
+				fmt.Fprintf(out, " %v\n", desc.Type)
+			default:
+				fmt.Fprintln(out)
+			}
+		}
+		return nil
+	}}
+}
+
+func setupExports(fs *flag.FlagSet) *runner {
+	return &runner{run: func(out io.Writer, file string, mod *types.Module) error {
+		for _, exp := range mod.SecExport {
+			fmt.Fprintf(out, "%s[%d] -> %q\n", exp.Desc.Kind, exp.Desc.Index, types.SafeName(exp.Name))
+		}
+		return nil
+	}}
+}
+
+func setupDisasm(fs *flag.FlagSet) *runner {
+	funcIdx := fs.Int64("func", -1, "index of the only function to print, -1 for all of them")
+	return &runner{
+		check: func() error {
+			if *funcIdx < -1 || *funcIdx > math.MaxUint32 {
+				return fmt.Errorf("invalid function index: %d", *funcIdx)
+			}
+			return nil
+		},
+		run: func(out io.Writer, file string, mod *types.Module) error {
+			if *funcIdx >= 0 {
+				if _, err := mod.Function(uint32(*funcIdx)); err != nil {
+					return err
+				}
+			}
+
+			v := &disassembler{out: out, only: *funcIdx}
+			if ns := mod.NameSection(); ns != nil {
+				v.names = ns.Functions
+			}
+			return types.Walk(mod, v)
+		},
+	}
+}
+
+// disassembler prints the instructions of the bodies, indented by their depth of blocks
+type disassembler struct {
+	types.BaseVisitor
+	out   io.Writer
+	only  int64 // index of the only function to print, -1 for all of them
+	names types.NameMap
+	depth int
+}
+
+func (d *disassembler) VisitFunction(f *types.Function) error {
+	if f.Imported() || (d.only >= 0 && int64(f.Index) != d.only) {
+		return types.SkipBody
+	}
+
+	fmt.Fprintf(d.out, "func[%d]", f.Index)
+	if name, ok := d.names[f.Index]; ok {
+		fmt.Fprintf(d.out, " <%s>", types.SafeName(name))
+	}
+	fmt.Fprintln(d.out, ":")
+	d.depth = 0
+	return nil
+}
+
+func (d *disassembler) VisitInstruction(funcIdx uint32, offset int64, ins *operator.Instruction) error {
+	switch ins.OpCode {
+	case operator.OpCodeEnd, operator.OpCodeElse, operator.OpCodeCatch, operator.OpCodeCatchAll, operator.OpCodeDelegate:
+		if d.depth > 0 {
+			d.depth--
+		}
+	}
+	fmt.Fprintf(d.out, " %06x: %s%s\n", offset, strings.Repeat("  ", d.depth), ins)
+	switch ins.OpCode {
+	case operator.OpCodeBlock, operator.OpCodeLoop, operator.OpCodeIf, operator.OpCodeElse, operator.OpCodeTry,
+		operator.OpCodeCatch, operator.OpCodeCatchAll:
+		d.depth++
+	}
+	return nil
+}
+
+func setupCustom(fs *flag.FlagSet) *runner {
+	name := fs.String("name", "", "write the bytes of the first custom section of this name instead of listing them")
+	return &runner{run: func(out io.Writer, file string, mod *types.Module) error {
+		if *name == "" {
+			for _, c := range mod.SecCustoms {
+				fmt.Fprintf(out, "%q size=%d\n", types.SafeName(c.Name), len(c.Bytes))
+			}
+			return nil
+		}
+
+		c := mod.CustomSection(*name)
+		if c == nil {
+			return fmt.Errorf("no custom section %q", *name)
+		}
+		_, err := out.Write(c.Bytes)
+		return err
+	}}
+}
@@ -0,0 +1,256 @@
+// Command wasm-decode decodes WASM modules and prints what they contain.
+//
+// Usage:
+//
+//	wasm-decode <command> [flags] [file ...]
+//
+// The modules are read from the files, or from stdin if none is given or a file is "-".
+// The exit code is 0 on success, 1 if a module fails to decode or to validate, and 2 on usage errors.
+// The errors are printed to stderr, as text or as JSON lines with -error-format=json.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/LBruyne/wasm-decode/feature"
+	"github.com/LBruyne/wasm-decode/types"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// runFunc prints what a command shows of the module decoded from file
+type runFunc func(out io.Writer, file string, mod *types.Module) error
+
+// runner is what a command runs once its flags are parsed
+type runner struct {
+	run runFunc
+	// check, if set, reports the invalid values of the flags of the command
+	check func() error
+	// separate, if set, replaces the header preceding the output of each module when there are several files,
+	// first tells whether nothing is written before
+	separate func(out io.Writer, file string, first bool)
+	// end, if set, writes what follows the outputs of the modules when there are several files
+	end func(out io.Writer)
+}
+
+type command struct {
+	name    string
+	summary string
+	// setup registers the flags of the command and returns what it runs
+	setup func(fs *flag.FlagSet) *runner
+}
+
+// header separates the outputs of the modules of several files by their names
+func header(out io.Writer, file string, first bool) {
+	if !first {
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintf(out, "%s:\n", file)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command line args and returns the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return exitOK
+	}
+
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		fmt.Fprintf(stderr, "wasm-decode: unknown command %q\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("wasm-decode "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: wasm-decode %s [flags] [file ...]\n\n%s\n\nflags:\n", cmd.name, cmd.summary)
+		fs.PrintDefaults()
+	}
+	var opts options
+	opts.register(fs)
+	r := cmd.setup(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	decodeOpts, err := opts.decodeOptions()
+	if err == nil && r.check != nil {
+		err = r.check()
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "wasm-decode: %v\n", err)
+		return exitUsage
+	}
+	separate := r.separate
+	if separate == nil {
+		separate = header
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	code := exitOK
+	first := true
+	for _, file := range files {
+		name := file
+		if file == "-" {
+			name = "<stdin>"
+		}
+
+		mod, err := decodeFile(stdin, file, decodeOpts)
+		if err == nil {
+			if len(files) > 1 {
+				separate(stdout, name, first)
+			}
+			first = false
+			err = r.run(stdout, name, mod)
+		}
+		if err != nil {
+			opts.report(stderr, name, err)
+			code = exitFailure
+		}
+	}
+	if len(files) > 1 && r.end != nil {
+		r.end(stdout)
+	}
+	return code
+}
+
+// decodeFile decodes the module of file, which is stdin if it is "-"
+func decodeFile(stdin io.Reader, file string, opts *types.DecodeOptions) (*types.Module, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = ioutil.ReadAll(stdin)
+	} else {
+		data, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mod := &types.Module{}
+	if err := mod.DecodeBytesWithOptions(data, opts); err != nil {
+		return nil, err
+	}
+	return mod, nil
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "usage: wasm-decode <command> [flags] [file ...]\n\ncommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nRun 'wasm-decode <command> -h' for the flags of a command.\n")
+}
+
+// options are the flags shared by all the commands
+type options struct {
+	features     string
+	recover      bool
+	allowUnknown bool
+	errorFormat  string
+}
+
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.features, "features", "wasm20",
+		`accepted proposals: "mvp", "wasm20", "all" or a comma separated list of names and sets`)
+	fs.BoolVar(&o.recover, "recover", false, "skip the sections which fail to decode instead of failing")
+	fs.BoolVar(&o.allowUnknown, "allow-unknown", false, "keep the sections of unknown id instead of failing")
+	fs.StringVar(&o.errorFormat, "error-format", "text", `format of the errors printed to stderr: "text" or "json"`)
+}
+
+func (o *options) decodeOptions() (*types.DecodeOptions, error) {
+	features, err := parseFeatures(o.features)
+	if err != nil {
+		return nil, err
+	}
+	if o.errorFormat != "text" && o.errorFormat != "json" {
+		return nil, fmt.Errorf("unknown error format: %s", o.errorFormat)
+	}
+
+	opts := types.DefaultDecodeOptions()
+	opts.Features = features
+	opts.Recover = o.recover
+	opts.AllowUnknownSections = o.allowUnknown
+	return opts, nil
+}
+
+// parseFeatures returns the union of the comma separated features and sets
+func parseFeatures(s string) (feature.Set, error) {
+	var set feature.Set
+	for _, name := range strings.Split(s, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "", "mvp":
+		case "wasm20":
+			set |= feature.Wasm20
+		case "all":
+			set |= feature.All
+		default:
+			f, err := feature.Parse(name)
+			if err != nil {
+				return 0, err
+			}
+			set = set.With(f)
+		}
+	}
+	return set, nil
+}
+
+// jsonError is an error printed with -error-format=json
+type jsonError struct {
+	File   string `json:"file"`
+	Offset *int64 `json:"offset,omitempty"` // offset in the module binary, if the error comes from the decoding
+	Error  string `json:"error"`
+}
+
+// report prints the error of file to w
+func (o *options) report(w io.Writer, file string, err error) {
+	if o.errorFormat != "json" {
+		fmt.Fprintf(w, "wasm-decode: %s: %v\n", file, err)
+		return
+	}
+
+	e := jsonError{File: file, Error: err.Error()}
+	var de *types.DecodeError
+	var diag *types.Diagnostic
+	if errors.As(err, &de) {
+		e.Offset, e.Error = &de.Offset, de.Err.Error()
+	} else if errors.As(err, &diag) {
+		e.Offset = &diag.Offset
+	}
+	_ = json.NewEncoder(w).Encode(e)
+}
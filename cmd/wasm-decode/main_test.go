@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/LBruyne/wasm-decode/builder"
+	"github.com/LBruyne/wasm-decode/operator"
+	"github.com/LBruyne/wasm-decode/types"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+var (
+	testFile = "../../examples/wasm/test.wasm"
+	fibFile  = "../../examples/wasm/fib.wasm"
+)
+
+// runArgs runs the command line args with stdin and returns the exit code, stdout and stderr
+func runArgs(stdin []byte, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, bytes.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestUsage(t *testing.T) {
+	code, _, stderr := runArgs(nil)
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "disasm")
+
+	code, _, stderr = runArgs(nil, "unknown")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `unknown command "unknown"`)
+
+	code, stdout, _ := runArgs(nil, "help")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "validate")
+
+	code, _, _ = runArgs(nil, "sections", "-no-such-flag", testFile)
+	assert.Equal(t, exitUsage, code)
+
+	code, _, stderr = runArgs(nil, "sections", "-features", "no-such-feature", testFile)
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "unknown feature")
+}
+
+func TestValidate(t *testing.T) {
+	code, stdout, stderr := runArgs(nil, "validate", testFile, fibFile)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, testFile+": ok\n"+fibFile+": ok\n", stdout)
+	assert.Empty(t, stderr)
+
+	data, err := ioutil.ReadFile(fibFile)
+	assert.Nil(t, err)
+	code, stdout, _ = runArgs(data, "validate", "-")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "<stdin>: ok\n", stdout)
+
+	code, stdout, stderr = runArgs(data[:len(data)-10], "validate", "-error-format=json")
+	assert.Equal(t, exitFailure, code)
+	assert.Empty(t, stdout)
+	var e jsonError
+	assert.Nil(t, json.Unmarshal([]byte(stderr), &e))
+	assert.Equal(t, "<stdin>", e.File)
+	if assert.NotNil(t, e.Offset) {
+		assert.True(t, *e.Offset > 0 && *e.Offset <= int64(len(data)))
+	}
+	assert.NotEmpty(t, e.Error)
+
+	code, _, stderr = runArgs([]byte("not wasm"), "validate")
+	assert.Equal(t, exitFailure, code)
+	assert.True(t, strings.HasPrefix(stderr, "wasm-decode: <stdin>: at offset "))
+
+	code, _, _ = runArgs(nil, "validate", "no-such-file.wasm")
+	assert.Equal(t, exitFailure, code)
+}
+
+func TestListings(t *testing.T) {
+	code, stdout, _ := runArgs(nil, "sections", fibFile)
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "     code start=0x0000004e end=0x00000057 size=7\n")
+	assert.Contains(t, stdout, `"name"`)
+
+	code, stdout, _ = runArgs(nil, "imports", testFile)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "func[0] <env.print_char> sig=0\n", stdout)
+
+	code, stdout, _ = runArgs(nil, "exports", testFile)
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "func[1] -> \"main\"\n")
+
+	code, stdout, _ = runArgs(nil, "custom", testFile, fibFile)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, testFile+":\n\"name\" size=887\n\n"+fibFile+":\n\"linking\" size=3\n\"name\" size=26\n", stdout)
+
+	code, stdout, _ = runArgs(nil, "custom", "-name", "linking", fibFile)
+	assert.Equal(t, exitOK, code)
+	assert.Len(t, stdout, 3)
+
+	code, _, stderr := runArgs(nil, "custom", "-name", "missing", fibFile)
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, stderr, `no custom section "missing"`)
+}
+
+func TestDisasm(t *testing.T) {
+	code, stdout, _ := runArgs(nil, "disasm", "-func", "1", fibFile)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "func[1] <fib>:\n 000053: call 0\n 000055: drop\n 000056: end\n", stdout)
+
+	code, stdout, _ = runArgs(nil, "disasm", testFile)
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "i32.store offset=24 align=4\n")
+
+	code, _, _ = runArgs(nil, "disasm", "-func", "100", fibFile)
+	assert.Equal(t, exitFailure, code)
+
+	// 1<<32 + 1 must not be taken as 1
+	code, stdout, _ = runArgs(nil, "disasm", "-func", "4294967297", fibFile)
+	assert.Equal(t, exitUsage, code)
+	assert.Empty(t, stdout)
+}
+
+func TestDump(t *testing.T) {
+	code, stdout, _ := runArgs(nil, "dump", fibFile)
+	assert.Equal(t, exitOK, code)
+	assert.True(t, strings.HasPrefix(stdout, "Version: 0x01000000\nType[2]:\n"))
+
+	type document struct {
+		File     string
+		Version  uint32
+		Sections []struct{ Name string }
+	}
+
+	// one object per file, with the features in the same document
+	code, stdout, _ = runArgs(nil, "dump", "-format", "json", "-detect", fibFile)
+	assert.Equal(t, exitOK, code)
+	var doc document
+	assert.Nil(t, json.Unmarshal([]byte(stdout), &doc))
+	assert.Equal(t, fibFile, doc.File)
+	assert.EqualValues(t, 1, doc.Version)
+	if assert.NotEmpty(t, doc.Sections) {
+		assert.Equal(t, "features", doc.Sections[len(doc.Sections)-1].Name)
+	}
+
+	// an array of the documents of several files, leaving out the ones which fail
+	code, stdout, _ = runArgs(nil, "dump", "-format=json", "-detect", testFile, "no-such-file.wasm", fibFile)
+	assert.Equal(t, exitFailure, code)
+	var docs []document
+	assert.Nil(t, json.Unmarshal([]byte(stdout), &docs))
+	if assert.Len(t, docs, 2) {
+		assert.Equal(t, testFile, docs[0].File)
+		assert.Equal(t, fibFile, docs[1].File)
+	}
+
+	code, stdout, _ = runArgs(nil, "dump", "-format=json", "no-such-file.wasm", "no-such-file.wasm")
+	assert.Equal(t, exitFailure, code)
+	assert.Nil(t, json.Unmarshal([]byte(stdout), &docs))
+	assert.Empty(t, docs)
+
+	// a YAML document per file
+	code, stdout, _ = runArgs(nil, "dump", "-format=yaml", testFile, fibFile)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, 2, strings.Count(stdout, "---\n"))
+	assert.Contains(t, stdout, "---\nfile: \""+fibFile+"\"\nversion: 1\n")
+
+	code, _, stderr := runArgs(nil, "dump", "-format", "xml", fibFile)
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "unknown format")
+}
+
+func TestValidateExports(t *testing.T) {
+	build := func(exports ...*types.ExportSegment) []byte {
+		b := builder.New()
+		b.AddFunction(b.AddType(nil, nil), nil, []byte{byte(operator.OpCodeEnd)})
+		mod, err := b.Module()
+		assert.Nil(t, err)
+		mod.SecExport = exports
+		bs, err := mod.Encode()
+		assert.Nil(t, err)
+		return bs
+	}
+	export := func(name string, idx uint32) *types.ExportSegment {
+		return &types.ExportSegment{Name: name, Desc: &types.ExportDescription{Kind: types.ExternalKindFunc, Index: idx}}
+	}
+
+	code, _, _ := runArgs(build(export("a", 0), export("b", 0)), "validate")
+	assert.Equal(t, exitOK, code)
+
+	code, _, stderr := runArgs(build(export("a", 0), export("a", 0)), "validate")
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, stderr, `duplicate name "a"`)
+
+	code, _, stderr = runArgs(build(export("a", 0), export("b", 1)), "validate")
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, stderr, `export[1] "b"`)
+}
@@ -4,7 +4,22 @@ import "errors"
 
 var (
 	ErrInvalidMagicNumber = errors.New("invalid magic number")
-	ErrInvalidVersion = errors.New("invalid version header")
+	ErrInvalidVersion     = errors.New("invalid version header")
 
-	ErrInvalidByte = errors.New("invalid byte")
+	ErrInvalidByte     = errors.New("invalid byte")
+	ErrInvalidUTF8     = errors.New("invalid UTF-8 encoding")
+	ErrFeatureDisabled = errors.New("feature disabled")
+	ErrUnknownSection  = errors.New("unknown section id")
+	ErrIndexOutOfRange = errors.New("index out of range")
+	ErrExportNotFound  = errors.New("export not found")
+	ErrUnbalancedBlock = errors.New("unbalanced block")
+	ErrUnknownLabel    = errors.New("unknown label")
+
+	ErrVectorTooLong          = errors.New("vector too long")
+	ErrStringTooLong          = errors.New("string too long")
+	ErrTooManyFunctions       = errors.New("too many functions")
+	ErrTooManyLocals          = errors.New("too many locals")
+	ErrFunctionTooLarge       = errors.New("function too large")
+	ErrCustomSectionsTooLarge = errors.New("custom sections too large")
+	ErrAllocLimitExceeded     = errors.New("allocation limit exceeded")
 )
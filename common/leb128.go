@@ -8,6 +8,9 @@ import (
 
 var (
 	ErrReadByte = errors.New("readByte failed")
+
+	ErrIntegerTooLarge              = errors.New("integer too large")
+	ErrIntegerRepresentationTooLong = errors.New("integer representation too long")
 )
 
 var sevenBits = [...]byte{
@@ -74,127 +77,206 @@ func EncodeUint32(num uint32) []byte {
 	return EncodeUint64(uint64(num))
 }
 
+// SizeUint64 returns the size in bytes of the shortest encoding of num
+func SizeUint64(num uint64) int {
+	n := 1
+	for num >= 0x80 {
+		num >>= 7
+		n++
+	}
+	return n
+}
+
+// SizeInt64 returns the size in bytes of the shortest encoding of num
+func SizeInt64(num int64) int {
+	n := 1
+	for num < -0x40 || num >= 0x40 {
+		num >>= 7
+		n++
+	}
+	return n
+}
+
 // DecodeUint32 decode the bytes to the uint32
 func DecodeUint32(r io.Reader) (ret uint32, num uint64, err error) {
-	const (
-		uint32Mask  uint32 = 1 << 7
-		uint32Mask2        = ^uint32Mask
-	)
-
-	//0,7,16,21,28,35
-	for shift := 0; shift < 35; shift += 7 {
-		b, err := readByteAsUint32(r)
-		if err != nil {
-			return 0, 0, fmt.Errorf("readByte failed: %w", err)
-		}
-		num++
-		ret |= (b & uint32Mask2) << shift
-		if b&uint32Mask == 0 {
-			break
-		}
-	}
-	return
+	v, n, err := decodeUnsignedReader(newByteSource(r), 32)
+	return uint32(v), uint64(n), err
 }
 
+// DecodeUint64 decode the bytes to the uint64
 func DecodeUint64(r io.Reader) (ret uint64, num uint64, err error) {
-	const (
-		uint64Mask  uint64 = 1 << 7
-		uint64Mask2        = ^uint64Mask
-	)
-	for shift := 0; shift < 64; shift += 7 {
-		b, err := readByteAsUint64(r)
-		if err != nil {
-			return 0, 0, fmt.Errorf("readByte failed: %w", err)
+	v, n, err := decodeUnsignedReader(newByteSource(r), 64)
+	return v, uint64(n), err
+}
+
+// DecodeInt32 decode the bytes to the int32
+func DecodeInt32(r io.Reader) (ret int32, num uint64, err error) {
+	v, n, err := decodeSignedReader(newByteSource(r), 32)
+	return int32(v), uint64(n), err
+}
+
+// DecodeInt64 decode the bytes to the int64
+func DecodeInt64(r io.Reader) (ret int64, num uint64, err error) {
+	v, n, err := decodeSignedReader(newByteSource(r), 64)
+	return v, uint64(n), err
+}
+
+// DecodeInt33 decode the bytes to the signed 33-bit integer of block types
+func DecodeInt33(r io.Reader) (ret int64, num uint64, err error) {
+	v, n, err := decodeSignedReader(newByteSource(r), 33)
+	return v, uint64(n), err
+}
+
+// DecodeUint32Bytes decode the uint32 at the beginning of b and returns the number of bytes it takes
+func DecodeUint32Bytes(b []byte) (uint32, int, error) {
+	v, n, err := decodeUnsigned(b, 32)
+	return uint32(v), n, err
+}
+
+// DecodeUint64Bytes decode the uint64 at the beginning of b and returns the number of bytes it takes
+func DecodeUint64Bytes(b []byte) (uint64, int, error) {
+	return decodeUnsigned(b, 64)
+}
+
+// DecodeInt32Bytes decode the int32 at the beginning of b and returns the number of bytes it takes
+func DecodeInt32Bytes(b []byte) (int32, int, error) {
+	v, n, err := decodeSigned(b, 32)
+	return int32(v), n, err
+}
+
+// DecodeInt64Bytes decode the int64 at the beginning of b and returns the number of bytes it takes
+func DecodeInt64Bytes(b []byte) (int64, int, error) {
+	return decodeSigned(b, 64)
+}
+
+// DecodeInt33Bytes decode the signed 33-bit integer of block types at the beginning of b
+// and returns the number of bytes it takes
+func DecodeInt33Bytes(b []byte) (int64, int, error) {
+	return decodeSigned(b, 33)
+}
+
+// decodeUnsigned decode an unsigned integer of the given bits. As the spec requires,
+// the encoding takes at most ceil(bits/7) bytes and the unused bits of the last byte must be 0.
+func decodeUnsigned(src []byte, bits int) (ret uint64, n int, err error) {
+	maxBytes := (bits + 6) / 7
+	for shift := 0; ; shift += 7 {
+		if n >= len(src) {
+			return 0, 0, fmt.Errorf("readByte failed: %w", io.ErrUnexpectedEOF)
 		}
-		num++
-		ret |= (b & uint64Mask2) << shift
-		if b&uint64Mask == 0 {
-			break
+		b := src[n]
+		n++
+
+		if n == maxBytes {
+			if b&0x80 != 0 {
+				return 0, 0, ErrIntegerRepresentationTooLong
+			}
+			if b>>(bits-shift) != 0 {
+				return 0, 0, ErrIntegerTooLarge
+			}
+		}
+
+		ret |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return ret, n, nil
 		}
 	}
-	return
 }
 
-func DecodeInt32(r io.Reader) (ret int32, num uint64, err error) {
-	const (
-		int32Mask  int32 = 1 << 7
-		int32Mask2       = ^int32Mask
-		int32Mask3       = 1 << 6
-		int32Mask4       = ^0
-	)
+// decodeSigned decode a signed integer of the given bits. As the spec requires,
+// the encoding takes at most ceil(bits/7) bytes and the unused bits of the last byte
+// must be the sign extension of the value.
+func decodeSigned(src []byte, bits int) (ret int64, n int, err error) {
+	maxBytes := (bits + 6) / 7
 	var shift int
-	var b int32
-	for shift < 35 {
-		b, err = readByteAsInt32(r)
-		if err != nil {
-			return 0, 0, fmt.Errorf("readByte failed: %w", err)
+	var b byte
+	for {
+		if n >= len(src) {
+			return 0, 0, fmt.Errorf("readByte failed: %w", io.ErrUnexpectedEOF)
+		}
+		b = src[n]
+		n++
+
+		if n == maxBytes {
+			if b&0x80 != 0 {
+				return 0, 0, ErrIntegerRepresentationTooLong
+			}
+			// bits from the sign bit to bit 6 must be all 0 or all 1
+			mask := byte(0x7f) >> (bits - shift - 1) << (bits - shift - 1)
+			if s := b & mask; s != 0 && s != mask {
+				return 0, 0, ErrIntegerTooLarge
+			}
 		}
-		num++
-		ret |= (b & int32Mask2) << shift
+
+		ret |= int64(b&0x7f) << shift
 		shift += 7
-		if b&int32Mask == 0 {
+		if b&0x80 == 0 {
 			break
 		}
 	}
 
-	if shift < 32 && (b&int32Mask3) == int32Mask3 {
-		ret |= int32Mask4 << shift
+	if shift < 64 && b&0x40 != 0 {
+		ret |= -1 << shift
 	}
-	return
+	return ret, n, nil
 }
 
-func DecodeInt64(r io.Reader) (ret int64, num uint64, err error) {
-	const (
-		int64Mask  int64 = 1 << 7
-		int64Mask2       = ^int64Mask
-		int64Mask3       = 1 << 6
-		int64Mask4       = ^0
-	)
-	var shift int
-	var b int64
-	for shift < 64 {
-		b, err = readByteAsInt64(r)
+// maxEncodedLen is the maximum size in bytes of an encoded integer of 64 bits
+const maxEncodedLen = 10
+
+// readEncoded reads the bytes of an integer of the given bits from src into buf
+func readEncoded(src io.ByteReader, bits int, buf *[maxEncodedLen]byte) (int, error) {
+	maxBytes := (bits + 6) / 7
+	for n := 0; n < maxBytes; n++ {
+		b, err := src.ReadByte()
 		if err != nil {
-			return 0, 0, fmt.Errorf("readByte failed: %w", err)
-		}
-		num++
-		ret |= (b & int64Mask2) << shift
-		shift += 7
-		if b&int64Mask == 0 {
-			break
+			if err == io.EOF && n > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, fmt.Errorf("readByte failed: %w", err)
 		}
-	}
 
-	if shift < 64 && (b&int64Mask3) == int64Mask3 {
-		ret |= int64Mask4 << shift
+		buf[n] = b
+		if b&0x80 == 0 {
+			return n + 1, nil
+		}
 	}
-	return
+	return maxBytes, nil
 }
 
-func readByteAsUint32(r io.Reader) (uint32, error) {
-	readByte, err := readByte(r)
-	return uint32(readByte), err
+func decodeUnsignedReader(src io.ByteReader, bits int) (uint64, int, error) {
+	var buf [maxEncodedLen]byte
+	n, err := readEncoded(src, bits, &buf)
+	if err != nil {
+		return 0, 0, err
+	}
+	return decodeUnsigned(buf[:n], bits)
 }
 
-func readByteAsInt32(r io.Reader) (int32, error) {
-	b := make([]byte, 1)
-	_, err := io.ReadFull(r, b)
-	return int32(b[0]), err
+func decodeSignedReader(src io.ByteReader, bits int) (int64, int, error) {
+	var buf [maxEncodedLen]byte
+	n, err := readEncoded(src, bits, &buf)
+	if err != nil {
+		return 0, 0, err
+	}
+	return decodeSigned(buf[:n], bits)
 }
 
-func readByteAsUint64(r io.Reader) (uint64, error) {
-	readByte, err := readByte(r)
-	return uint64(readByte), err
+// newByteSource returns r if it reads byte by byte already, otherwise wraps it
+func newByteSource(r io.Reader) io.ByteReader {
+	if br, ok := r.(io.ByteReader); ok {
+		return br
+	}
+	return &readerSource{r: r}
 }
 
-func readByteAsInt64(r io.Reader) (int64, error) {
-	readByte, err := readByte(r)
-	return int64(readByte), err
+type readerSource struct {
+	r   io.Reader
+	buf [1]byte
 }
 
-// readByte read one byte from io.Reader
-func readByte(r io.Reader) (byte, error) {
-	p := make([]byte, 1)
-	_, err := r.Read(p)
-	return p[0], err
+func (s *readerSource) ReadByte() (byte, error) {
+	if _, err := io.ReadFull(s.r, s.buf[:]); err != nil {
+		return 0, err
+	}
+	return s.buf[0], nil
 }
@@ -0,0 +1,110 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestDecodeUint32(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+		want uint32
+		n    int
+		err  error
+	}{
+		{"zero", []byte{0x00}, 0, 1, nil},
+		{"padded_zero", []byte{0x80, 0x00}, 0, 2, nil},
+		{"max", []byte{0xff, 0xff, 0xff, 0xff, 0x0f}, math.MaxUint32, 5, nil},
+		{"unused_bits", []byte{0x80, 0x80, 0x80, 0x80, 0x10}, 0, 0, ErrIntegerTooLarge},
+		{"too_long", []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, 0, 0, ErrIntegerRepresentationTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, n, err := DecodeUint32Bytes(tt.in)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.Equal(t, tt.want, v)
+			assert.Equal(t, tt.n, n)
+
+			rv, rn, err := DecodeUint32(bytes.NewBuffer(tt.in))
+			assert.True(t, errors.Is(err, tt.err))
+			assert.Equal(t, tt.want, rv)
+			assert.Equal(t, uint64(tt.n), rn)
+		})
+	}
+}
+
+func TestDecodeInt32(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+		want int32
+		err  error
+	}{
+		{"minus_one", []byte{0x7f}, -1, nil},
+		{"padded_minus_one", []byte{0xff, 0xff, 0xff, 0xff, 0x7f}, -1, nil},
+		{"min", []byte{0x80, 0x80, 0x80, 0x80, 0x78}, math.MinInt32, nil},
+		{"max", []byte{0xff, 0xff, 0xff, 0xff, 0x07}, math.MaxInt32, nil},
+		{"bad_sign_extension", []byte{0x80, 0x80, 0x80, 0x80, 0x70}, 0, ErrIntegerTooLarge},
+		{"positive_overflow", []byte{0xff, 0xff, 0xff, 0xff, 0x0f}, 0, ErrIntegerTooLarge},
+		{"too_long", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}, 0, ErrIntegerRepresentationTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, _, err := DecodeInt32Bytes(tt.in)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.Equal(t, tt.want, v)
+
+			rv, _, err := DecodeInt32(bytes.NewBuffer(tt.in))
+			assert.True(t, errors.Is(err, tt.err))
+			assert.Equal(t, tt.want, rv)
+		})
+	}
+}
+
+func TestDecode64(t *testing.T) {
+	u, n, err := DecodeUint64Bytes([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), u)
+	assert.Equal(t, 10, n)
+
+	_, _, err = DecodeUint64Bytes([]byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x02})
+	assert.True(t, errors.Is(err, ErrIntegerTooLarge))
+
+	i, _, err := DecodeInt64Bytes([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), i)
+
+	_, _, err = DecodeInt64Bytes([]byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x7e})
+	assert.True(t, errors.Is(err, ErrIntegerTooLarge))
+
+	_, _, err = DecodeInt64Bytes([]byte{0x80, 0x80})
+	assert.Error(t, err)
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, 63, 64, -64, -65, 1 << 20, math.MaxInt32, math.MinInt32, math.MaxInt64, math.MinInt64} {
+		b := EncodeInt64(v)
+		assert.Equal(t, len(b), SizeInt64(v))
+
+		got, n, err := DecodeInt64Bytes(b)
+		assert.Nil(t, err)
+		assert.Equal(t, v, got)
+		assert.Equal(t, len(b), n)
+	}
+
+	for _, v := range []uint64{0, 1, 127, 128, 1 << 32, math.MaxUint64} {
+		b := EncodeUint64(v)
+		assert.Equal(t, len(b), SizeUint64(v))
+
+		got, n, err := DecodeUint64Bytes(b)
+		assert.Nil(t, err)
+		assert.Equal(t, v, got)
+		assert.Equal(t, len(b), n)
+	}
+}
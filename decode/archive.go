@@ -0,0 +1,107 @@
+package decode
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"github.com/LBruyne/wasm-decode/types"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// EntryFunc receives the module decoded from the entry at path of an archive or a file system,
+// or the error of decoding it. Returning an error stops the walk with it.
+type EntryFunc func(path string, mod *types.Module, err error) error
+
+// IsModuleName reports whether name is of a module file, that is .wasm or gzip-compressed .wasm.gz
+func IsModuleName(name string) bool {
+	return strings.HasSuffix(name, ".wasm") || isGzipName(name)
+}
+
+func isGzipName(name string) bool {
+	return strings.HasSuffix(name, ".wasm.gz")
+}
+
+// DecodeGzip decodes a WASM module from a gzip-compressed stream, such as a .wasm.gz file
+func DecodeGzip(r io.Reader, opts *DecodeOptions) (*types.Module, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("read gzip header: %w", err)
+	}
+	defer zr.Close()
+
+	return DecodeModuleWithOptions(zr, opts)
+}
+
+// DecodeFS decodes the module file name of fsys, e.g. an embed.FS.
+// A name ending with .gz is decompressed first.
+func DecodeFS(fsys fs.FS, name string, opts *DecodeOptions) (*types.Module, error) {
+	if isGzipName(name) {
+		f, err := fsys.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("open file %v: %w", name, err)
+		}
+		defer f.Close()
+
+		return DecodeGzip(f, opts)
+	}
+
+	bs, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("read file %v: %w", name, err)
+	}
+	return DecodeBytesWithOptions(bs, opts)
+}
+
+// WalkFS decodes every module file of fsys in lexical order, see IsModuleName
+func WalkFS(fsys fs.FS, opts *DecodeOptions, fn EntryFunc) error {
+	return fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !IsModuleName(path) {
+			return nil
+		}
+
+		mod, err := DecodeFS(fsys, path, opts)
+		return fn(path, mod, err)
+	})
+}
+
+// DecodeZip decodes every module file of the zip archive of size bytes read from ra, one by one
+func DecodeZip(ra io.ReaderAt, size int64, opts *DecodeOptions, fn EntryFunc) error {
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return fmt.Errorf("read zip archive: %w", err)
+	}
+	return WalkFS(zr, opts, fn)
+}
+
+// DecodeTar decodes every module file of the tar archive read from r, one by one in the order of the archive.
+// A compressed archive such as .tar.gz must be decompressed by r.
+func DecodeTar(r io.Reader, opts *DecodeOptions, fn EntryFunc) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("read tar archive: %w", err)
+		}
+		if !hdr.FileInfo().Mode().IsRegular() || !IsModuleName(hdr.Name) {
+			continue
+		}
+
+		var mod *types.Module
+		if isGzipName(hdr.Name) {
+			mod, err = DecodeGzip(tr, opts)
+		} else {
+			mod, err = DecodeModuleWithOptions(tr, opts)
+		}
+		if err := fn(hdr.Name, mod, err); err != nil {
+			return err
+		}
+	}
+}
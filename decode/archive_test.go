@@ -0,0 +1,111 @@
+package decode
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"github.com/LBruyne/wasm-decode/types"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+	"testing/fstest"
+)
+
+// archiveFiles returns the files put into the archives of the tests, by name
+func archiveFiles(t *testing.T) map[string][]byte {
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, err = zw.Write(buf)
+	assert.Nil(t, err)
+	assert.Nil(t, zw.Close())
+
+	return map[string][]byte{
+		"a/test.wasm":    buf,
+		"a/test.wasm.gz": gz.Bytes(),
+		"b/broken.wasm":  buf[:len(buf)-1],
+		"b/README.md":    []byte("not a module"),
+	}
+}
+
+// collect returns an EntryFunc which keeps the error of each entry by path
+func collect(t *testing.T, errs map[string]error) EntryFunc {
+	return func(path string, mod *types.Module, err error) error {
+		if err == nil {
+			assert.NotEmpty(t, mod.SecCode)
+		}
+		errs[path] = err
+		return nil
+	}
+}
+
+func assertEntries(t *testing.T, errs map[string]error) {
+	assert.Len(t, errs, 3)
+	assert.Nil(t, errs["a/test.wasm"])
+	assert.Nil(t, errs["a/test.wasm.gz"])
+	assert.Error(t, errs["b/broken.wasm"])
+}
+
+func TestDecodeFS(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for name, data := range archiveFiles(t) {
+		fsys[name] = &fstest.MapFile{Data: data}
+	}
+
+	mod, err := DecodeFS(fsys, "a/test.wasm", nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, mod)
+
+	mod, err = DecodeFS(fsys, "a/test.wasm.gz", nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, mod)
+
+	_, err = DecodeFS(fsys, "a/missing.wasm", nil)
+	assert.Error(t, err)
+
+	errs := map[string]error{}
+	assert.Nil(t, WalkFS(fsys, nil, collect(t, errs)))
+	assertEntries(t, errs)
+
+	// the error of fn stops the walk
+	errStop := errors.New("stop")
+	err = WalkFS(fsys, nil, func(path string, mod *types.Module, err error) error { return errStop })
+	assert.Equal(t, errStop, err)
+}
+
+func TestDecodeZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range archiveFiles(t) {
+		w, err := zw.Create(name)
+		assert.Nil(t, err)
+		_, err = w.Write(data)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, zw.Close())
+
+	errs := map[string]error{}
+	assert.Nil(t, DecodeZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()), nil, collect(t, errs)))
+	assertEntries(t, errs)
+
+	assert.Error(t, DecodeZip(bytes.NewReader([]byte("not a zip")), 9, nil, collect(t, errs)))
+}
+
+func TestDecodeTar(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, data := range archiveFiles(t) {
+		assert.Nil(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))}))
+		_, err := tw.Write(data)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tw.Close())
+
+	errs := map[string]error{}
+	assert.Nil(t, DecodeTar(&buf, nil, collect(t, errs)))
+	assertEntries(t, errs)
+}
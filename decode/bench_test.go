@@ -0,0 +1,160 @@
+package decode
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/types"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadExamples reads every module in examples/wasm
+func loadExamples(b *testing.B) map[string][]byte {
+	paths, err := filepath.Glob("../examples/wasm/*.wasm")
+	if err != nil || len(paths) == 0 {
+		b.Fatalf("no example modules: %v", err)
+	}
+
+	ret := make(map[string][]byte, len(paths))
+	for _, p := range paths {
+		buf, err := ioutil.ReadFile(p)
+		if err != nil {
+			b.Fatal(err)
+		}
+		ret[strings.TrimSuffix(filepath.Base(p), ".wasm")] = buf
+	}
+	return ret
+}
+
+func BenchmarkDecodeModule(b *testing.B) {
+	for name, buf := range loadExamples(b) {
+		buf := buf
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(buf)))
+			for i := 0; i < b.N; i++ {
+				if _, err := DecodeModule(bytes.NewReader(buf)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeBytes(b *testing.B) {
+	for name, buf := range loadExamples(b) {
+		buf := buf
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(buf)))
+			for i := 0; i < b.N; i++ {
+				if _, err := DecodeBytes(buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeReaderAt(b *testing.B) {
+	for name, buf := range loadExamples(b) {
+		buf := buf
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(buf)))
+			for i := 0; i < b.N; i++ {
+				if _, err := DecodeReaderAt(bytes.NewReader(buf), int64(len(buf))); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// syntheticModule builds a module of n functions, each exported and declaring locals,
+// which stands for a mid-size module produced by a compiler
+func syntheticModule(n int) []byte {
+	section := func(id byte, count int, items []byte) []byte {
+		payload := append(common.EncodeUint32(uint32(count)), items...)
+		ret := append([]byte{id}, common.EncodeUint32(uint32(len(payload)))...)
+		return append(ret, payload...)
+	}
+
+	var funcs, exports, codes []byte
+	for i := 0; i < n; i++ {
+		funcs = append(funcs, 0x00)
+
+		name := fmt.Sprintf("func_%d", i)
+		exports = append(exports, common.EncodeUint32(uint32(len(name)))...)
+		exports = append(exports, name...)
+		exports = append(exports, byte(types.ExternalKindFunc))
+		exports = append(exports, common.EncodeUint32(uint32(i))...)
+
+		// (local i64 f32) local.get 0 i32.const 1 i32.add end
+		body := []byte{0x02, 0x01, 0x7e, 0x01, 0x7d, 0x20, 0x00, 0x41, 0x01, 0x6a, 0x0b}
+		codes = append(codes, common.EncodeUint32(uint32(len(body)))...)
+		codes = append(codes, body...)
+	}
+
+	buf := module()
+	buf = append(buf, section(byte(types.SectionIDType), 1, []byte{0x60, 0x01, 0x7f, 0x01, 0x7f})...)
+	buf = append(buf, section(byte(types.SectionIDFunction), n, funcs)...)
+	buf = append(buf, section(byte(types.SectionIDExport), n, exports)...)
+	buf = append(buf, section(byte(types.SectionIDCode), n, codes)...)
+	return buf
+}
+
+func BenchmarkDecodeSynthetic(b *testing.B) {
+	buf := syntheticModule(10000)
+
+	b.Run("module", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(buf)))
+		for i := 0; i < b.N; i++ {
+			if _, err := DecodeModule(bytes.NewReader(buf)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(buf)))
+		for i := 0; i < b.N; i++ {
+			if _, err := DecodeBytes(buf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// TestDecodeAllocs keeps the allocations per module from growing again,
+// the byte by byte decoder made 294 for test.wasm, 97 for fib.wasm and 209912 for the synthetic module
+func TestDecodeAllocs(t *testing.T) {
+	test, err := ioutil.ReadFile("../examples/wasm/test.wasm")
+	assert.Nil(t, err)
+	fib, err := ioutil.ReadFile("../examples/wasm/fib.wasm")
+	assert.Nil(t, err)
+
+	cases := []struct {
+		name string
+		buf  []byte
+		max  float64
+	}{
+		{"test", test, 50},
+		{"fib", fib, 40},
+		{"synthetic", syntheticModule(10000), 100},
+	}
+	for _, c := range cases {
+		allocs := testing.AllocsPerRun(10, func() {
+			if _, err := DecodeModule(bytes.NewReader(c.buf)); err != nil {
+				t.Fatal(err)
+			}
+		})
+		assert.LessOrEqual(t, allocs, c.max, c.name)
+	}
+}
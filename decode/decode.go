@@ -1,17 +1,71 @@
 package decode
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"github.com/LBruyne/wasm-decode/types"
 	"io"
 	"io/ioutil"
 )
 
+// DecodeOptions controls how a module is decoded, see types.DecodeOptions
+type DecodeOptions = types.DecodeOptions
+
+// DefaultDecodeOptions returns the options used by the functions which do not take any
+func DefaultDecodeOptions() *DecodeOptions {
+	return types.DefaultDecodeOptions()
+}
+
 // DecodeModule decodes a WASM module from io.Reader which contains the bytes streeam of .wasm file
 func DecodeModule(r io.Reader) (mod *types.Module, err error) {
+	return DecodeModuleWithOptions(r, nil)
+}
+
+// DecodeModuleWithOptions is like DecodeModule but decodes according to opts
+func DecodeModuleWithOptions(r io.Reader, opts *DecodeOptions) (mod *types.Module, err error) {
+	mod = &types.Module{}
+	if err := mod.DecodeWithOptions(r, opts); err != nil {
+		return nil, fmt.Errorf("decode module: %w", err)
+	}
+	return mod, nil
+}
+
+// DecodeModuleContext is like DecodeModuleWithOptions but stops with the error of ctx once it is done,
+// which is checked between sections and between code segments. Set opts.Progress to follow the decoding.
+func DecodeModuleContext(ctx context.Context, r io.Reader, opts *DecodeOptions) (mod *types.Module, err error) {
+	mod = &types.Module{}
+	if err := mod.DecodeContext(ctx, r, opts); err != nil {
+		return nil, fmt.Errorf("decode module: %w", err)
+	}
+	return mod, nil
+}
+
+// DecodeBytes decodes a WASM module from the bytes of .wasm file without copying them,
+// code bodies, data segments and custom sections of the module refer to the memory of b.
+func DecodeBytes(b []byte) (mod *types.Module, err error) {
+	return DecodeBytesWithOptions(b, nil)
+}
+
+// DecodeBytesWithOptions is like DecodeBytes but decodes according to opts
+func DecodeBytesWithOptions(b []byte, opts *DecodeOptions) (mod *types.Module, err error) {
 	mod = &types.Module{}
-	if err := mod.Decode(r); err != nil {
+	if err := mod.DecodeBytesWithOptions(b, opts); err != nil {
+		return nil, fmt.Errorf("decode module: %w", err)
+	}
+	return mod, nil
+}
+
+// DecodeReaderAt decodes a WASM module of size bytes from io.ReaderAt, such as an os.File or
+// a memory-mapped file. Code bodies and data segments are loaded lazily from ra,
+// so ra must stay readable while the module is in use.
+func DecodeReaderAt(ra io.ReaderAt, size int64) (mod *types.Module, err error) {
+	return DecodeReaderAtWithOptions(ra, size, nil)
+}
+
+// DecodeReaderAtWithOptions is like DecodeReaderAt but decodes according to opts
+func DecodeReaderAtWithOptions(ra io.ReaderAt, size int64, opts *DecodeOptions) (mod *types.Module, err error) {
+	mod = &types.Module{}
+	if err := mod.DecodeReaderAtWithOptions(ra, size, opts); err != nil {
 		return nil, fmt.Errorf("decode module: %w", err)
 	}
 	return mod, nil
@@ -23,9 +77,23 @@ func DecodeFile(fn string) (*types.Module, error) {
 		return nil, fmt.Errorf("read file %v: %w", fn, err)
 	}
 
-	if mod, err := DecodeModule(bytes.NewBuffer(bs)); err != nil {
+	if mod, err := DecodeBytes(bs); err != nil {
 		return nil, fmt.Errorf("decode bytes: %w", err)
 	} else {
 		return mod, nil
 	}
 }
+
+// DecodeFileContext is like DecodeFile but decodes according to opts and stops with the error of ctx once it is done
+func DecodeFileContext(ctx context.Context, fn string, opts *DecodeOptions) (*types.Module, error) {
+	bs, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, fmt.Errorf("read file %v: %w", fn, err)
+	}
+
+	mod := &types.Module{}
+	if err := mod.DecodeBytesContext(ctx, bs, opts); err != nil {
+		return nil, fmt.Errorf("decode bytes: %w", err)
+	}
+	return mod, nil
+}
@@ -2,18 +2,33 @@ package decode
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"github.com/LBruyne/wasm-decode/builder"
 	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/feature"
+	"github.com/LBruyne/wasm-decode/operator"
+	"github.com/LBruyne/wasm-decode/types"
 	"github.com/stretchr/testify/assert"
 	"io"
 	"io/ioutil"
+	"runtime"
 	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 var (
 	fileName = "../examples/wasm/test.wasm"
+	// header is the magic number and the version which begin the binaries of the tests
+	header = []byte{0x00, 0x61, 0x73, 0x6D, 0x01, 0x00, 0x00, 0x00}
 )
 
+// module returns the binary of the sections following the header
+func module(sections ...byte) []byte {
+	return append(append([]byte{}, header...), sections...)
+}
+
 func TestDecodeFile(t *testing.T) {
 	mod, err := DecodeFile(fileName)
 	assert.Nil(t, err)
@@ -51,9 +66,707 @@ func TestDecodeModule(t *testing.T) {
 	})
 
 	t.Run("read_section_fail", func(t *testing.T) {
-		mod, err := DecodeModule(bytes.NewBuffer([]byte{0x00, 0x61, 0x73, 0x6D, 0x01, 0x00, 0x00, 0x00, 0x11, 0x00}))
+		mod, err := DecodeModule(bytes.NewBuffer(module(0x11, 0x00)))
 		assert.Nil(t, mod)
 		assert.Error(t, err)
 		assert.True(t, strings.Contains(err.Error(), "readSections failed"))
 	})
 }
+
+func TestDecodeBytes(t *testing.T) {
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+
+	mod, err := DecodeBytes(buf)
+	assert.Nil(t, err)
+	assert.NotNil(t, mod)
+
+	// code bodies and data segments share memory with the input
+	streamed, err := DecodeModule(bytes.NewBuffer(buf))
+	assert.Nil(t, err)
+	assert.Equal(t, len(streamed.SecCode), len(mod.SecCode))
+	for i, c := range mod.SecCode {
+		assert.Equal(t, streamed.SecCode[i].Body, c.Body)
+		assert.Equal(t, &buf[c.BodyOffset], &c.Body[0])
+	}
+	for _, d := range mod.SecData {
+		assert.Equal(t, &buf[d.InitOffset], &d.Init[0])
+	}
+
+	mod, err = DecodeBytes(buf[:len(buf)-10])
+	assert.Nil(t, mod)
+	assert.Error(t, err)
+}
+
+func TestDecodeReaderAt(t *testing.T) {
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+
+	streamed, err := DecodeModule(bytes.NewBuffer(buf))
+	assert.Nil(t, err)
+
+	mod, err := DecodeReaderAt(bytes.NewReader(buf), int64(len(buf)))
+	assert.Nil(t, err)
+	assert.NotNil(t, mod)
+
+	for i, c := range mod.SecCode {
+		assert.Nil(t, c.Body)
+		body, err := c.LoadBody()
+		assert.Nil(t, err)
+		assert.Equal(t, streamed.SecCode[i].Body, body)
+		assert.Equal(t, streamed.SecCode[i].Locals, c.Locals)
+	}
+	for i, d := range mod.SecData {
+		assert.Nil(t, d.Init)
+		init, err := d.LoadInit()
+		assert.Nil(t, err)
+		assert.Equal(t, streamed.SecData[i].Init, init)
+	}
+}
+
+// allocated returns the number of bytes allocated by f
+func allocated(f func()) uint64 {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	f()
+	runtime.ReadMemStats(&after)
+	return after.TotalAlloc - before.TotalAlloc
+}
+
+func TestDecodeOptions(t *testing.T) {
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+
+	t.Run("vector_too_long", func(t *testing.T) {
+		// type section declaring 0xffffffff function types in 5 bytes
+		bs := module(0x01, 0x05, 0xff, 0xff, 0xff, 0xff, 0x0f)
+
+		mod, err := DecodeModule(bytes.NewBuffer(bs))
+		assert.Nil(t, mod)
+		assert.True(t, errors.Is(err, common.ErrVectorTooLong))
+
+		mod, err = DecodeBytesWithOptions(bs, &DecodeOptions{})
+		assert.Nil(t, mod)
+		assert.True(t, errors.Is(err, common.ErrVectorTooLong))
+	})
+
+	t.Run("string_too_long", func(t *testing.T) {
+		mod, err := DecodeBytesWithOptions(buf, &DecodeOptions{MaxStringLen: 4})
+		assert.Nil(t, mod)
+		assert.True(t, errors.Is(err, common.ErrStringTooLong))
+	})
+
+	t.Run("too_many_functions", func(t *testing.T) {
+		mod, err := DecodeBytesWithOptions(buf, &DecodeOptions{MaxFunctions: 5})
+		assert.Nil(t, mod)
+		assert.True(t, errors.Is(err, common.ErrTooManyFunctions))
+
+		// the bodies of the code section count along with the imported functions,
+		// even without a function section
+		b := builder.New()
+		sig := b.AddType(nil, nil)
+		b.ImportFunc("env", "f", sig)
+		b.AddFunction(sig, nil, []byte{byte(operator.OpCodeEnd)})
+		m, err := b.Module()
+		assert.Nil(t, err)
+		m.SecFunction = nil
+		bs, err := m.Encode()
+		assert.Nil(t, err)
+
+		mod, err = DecodeBytesWithOptions(bs, &DecodeOptions{MaxFunctions: 1})
+		assert.Nil(t, mod)
+		assert.True(t, errors.Is(err, common.ErrTooManyFunctions))
+	})
+
+	t.Run("too_many_locals", func(t *testing.T) {
+		// one function whose two local declarations overflow uint32
+		bs := module(
+			0x01, 0x04, 0x01, 0x60, 0x00, 0x00, // type section
+			0x03, 0x02, 0x01, 0x00, // function section
+			0x0a, 0x10, 0x01, 0x0e, 0x02, 0xff, 0xff, 0xff, 0xff, 0x0f, 0x7f, 0xff, 0xff, 0xff, 0xff, 0x0f, 0x7f, 0x0b, // code section
+		)
+
+		mod, err := DecodeBytesWithOptions(bs, &DecodeOptions{})
+		assert.Nil(t, mod)
+		assert.True(t, errors.Is(err, common.ErrTooManyLocals))
+	})
+
+	t.Run("function_too_large", func(t *testing.T) {
+		mod, err := DecodeBytesWithOptions(buf, &DecodeOptions{MaxFunctionSize: 8})
+		assert.Nil(t, mod)
+		assert.True(t, errors.Is(err, common.ErrFunctionTooLarge))
+	})
+
+	t.Run("declared_sizes_not_allocated", func(t *testing.T) {
+		// a custom section of 0x3ffffff0 bytes named "a", which ends after its name
+		custom := module(0x00, 0xf0, 0xff, 0xff, 0xff, 0x03, 0x01, 'a')
+		assert.Len(t, custom, 16)
+		// a code segment of 0x3fffffe0 bytes, which ends after its locals
+		code := module(
+			0x01, 0x04, 0x01, 0x60, 0x00, 0x00, // type section
+			0x03, 0x02, 0x01, 0x00, // function section
+			0x0a, 0xf0, 0xff, 0xff, 0xff, 0x03, 0x01, 0xe0, 0xff, 0xff, 0xff, 0x03, 0x00, // code section
+		)
+		// an unknown section of 0x3ffffff0 bytes, which is captured
+		unknown := module(0x20, 0xf0, 0xff, 0xff, 0xff, 0x03, 0x00)
+
+		mod, err := DecodeModule(bytes.NewReader(custom))
+		assert.Nil(t, mod)
+		assert.True(t, errors.Is(err, common.ErrCustomSectionsTooLarge))
+
+		for _, bs := range [][]byte{custom, code, unknown} {
+			n := allocated(func() {
+				mod, err := DecodeModuleWithOptions(bytes.NewReader(bs), &DecodeOptions{AllowUnknownSections: true})
+				assert.Nil(t, mod)
+				assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+			})
+			assert.Less(t, n, uint64(1<<20))
+		}
+	})
+
+	t.Run("custom_sections_too_large", func(t *testing.T) {
+		mod, err := DecodeBytesWithOptions(buf, &DecodeOptions{MaxCustomSectionBytes: 10})
+		assert.Nil(t, mod)
+		assert.True(t, errors.Is(err, common.ErrCustomSectionsTooLarge))
+	})
+
+	t.Run("alloc_limit_exceeded", func(t *testing.T) {
+		mod, err := DecodeModuleWithOptions(bytes.NewBuffer(buf), &DecodeOptions{MaxAllocBytes: 1024})
+		assert.Nil(t, mod)
+		assert.True(t, errors.Is(err, common.ErrAllocLimitExceeded))
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		mod, err := DecodeModuleWithOptions(bytes.NewBuffer(buf), &DecodeOptions{})
+		assert.Nil(t, err)
+		assert.NotNil(t, mod)
+	})
+}
+
+func TestDecodeFeatures(t *testing.T) {
+	tests := []struct {
+		name    string
+		section []byte
+	}{
+		{"multi_value", []byte{0x01, 0x06, 0x01, 0x60, 0x00, 0x02, 0x7f, 0x7f}},
+		{"mutable_global_import", []byte{0x02, 0x08, 0x01, 0x01, 0x6d, 0x01, 0x67, 0x03, 0x7f, 0x01}},
+		{"passive_data", []byte{0x0b, 0x04, 0x01, 0x01, 0x01, 0xff}},
+		{"data_count", []byte{0x0c, 0x01, 0x00}},
+		{"externref_table", []byte{0x04, 0x04, 0x01, 0x6f, 0x00, 0x01}},
+		{"v128_global", []byte{0x06, 0x16, 0x01, 0x7b, 0x00, 0xfd, 0x0c,
+			0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x0b}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := module(tt.section...)
+
+			mod, err := DecodeBytesWithOptions(bs, &DecodeOptions{Features: feature.MVP})
+			assert.Nil(t, mod)
+			assert.True(t, errors.Is(err, common.ErrFeatureDisabled))
+
+			mod, err = DecodeBytes(bs)
+			assert.Nil(t, err)
+			assert.NotNil(t, mod)
+		})
+	}
+
+	t.Run("instructions", func(t *testing.T) {
+		build := func(body ...byte) []byte {
+			b := builder.New()
+			b.AddFunction(b.AddType(nil, nil), nil, append(body, byte(operator.OpCodeEnd)))
+			bs, err := b.Bytes()
+			assert.Nil(t, err)
+			return bs
+		}
+		extend := build(byte(operator.OpCodeI32Const), 0x01, byte(operator.OpCodeI32Extend8s), byte(operator.OpCodeDrop))
+		tailCall := build(byte(operator.OpCodeReturnCall), 0x00)
+
+		mod, err := DecodeBytesWithOptions(extend, &DecodeOptions{Features: feature.MVP})
+		assert.Nil(t, mod)
+		assert.True(t, errors.Is(err, common.ErrFeatureDisabled))
+		assert.Contains(t, err.Error(), "i32.extend8_s")
+
+		mod, err = DecodeBytes(extend)
+		assert.Nil(t, err)
+		assert.NotNil(t, mod)
+
+		// tail calls are not in WASM 2.0
+		mod, err = DecodeModule(bytes.NewReader(tailCall))
+		assert.Nil(t, mod)
+		assert.True(t, errors.Is(err, common.ErrFeatureDisabled))
+
+		opts := DefaultDecodeOptions()
+		opts.Features = opts.Features.With(feature.TailCall)
+		mod, err = DecodeBytesWithOptions(tailCall, opts)
+		assert.Nil(t, err)
+		assert.NotNil(t, mod)
+
+		// the bodies decoded lazily are checked once loaded
+		mod, err = DecodeReaderAtWithOptions(bytes.NewReader(extend), int64(len(extend)), &DecodeOptions{Features: feature.MVP})
+		assert.Nil(t, err)
+		_, err = mod.SecCode[0].LoadBody()
+		assert.True(t, errors.Is(err, common.ErrFeatureDisabled))
+	})
+
+	t.Run("unknown_section", func(t *testing.T) {
+		bs := module(0x11, 0x01, 0x00)
+
+		mod, err := DecodeBytes(bs)
+		assert.Nil(t, mod)
+		assert.Error(t, err)
+
+		mod, err = DecodeBytesWithOptions(bs, &DecodeOptions{AllowUnknownSections: true})
+		assert.Nil(t, err)
+		assert.NotNil(t, mod)
+	})
+}
+
+func TestDetectFeatures(t *testing.T) {
+	mod, err := DecodeFile(fileName)
+	assert.Nil(t, err)
+
+	rp, err := mod.DetectFeatures()
+	assert.Nil(t, err)
+	assert.Equal(t, feature.MVP, rp.Features)
+	assert.Empty(t, rp.Usages)
+
+	bs := module(
+		0x01, 0x06, 0x01, 0x60, 0x00, 0x02, 0x7f, 0x7f, // type section: [] -> [i32 i32]
+		0x02, 0x08, 0x01, 0x01, 0x6d, 0x01, 0x67, 0x03, 0x7f, 0x01, // import section: mutable global m.g
+		0x03, 0x02, 0x01, 0x00, // function section
+		0x0a, 0x09, 0x01, 0x07, 0x00, 0x41, 0x01, 0xc0, 0x41, 0x02, 0x0b, // code section: i32.extend8_s
+		0x00, 0x1a, 0x0f, 't', 'a', 'r', 'g', 'e', 't', '_', 'f', 'e', 'a', 't', 'u', 'r', 'e', 's',
+		0x01, '+', 0x07, 's', 'i', 'm', 'd', '1', '2', '8', // custom section: +simd128
+	)
+	mod, err = DecodeBytes(bs)
+	assert.Nil(t, err)
+
+	rp, err = mod.DetectFeatures()
+	assert.Nil(t, err)
+	assert.Equal(t, feature.MVP.With(feature.MultiValue, feature.MutableGlobals, feature.SignExtension, feature.SIMD), rp.Features)
+	assert.Equal(t, 4, len(rp.Usages))
+
+	assert.Equal(t, feature.MultiValue, rp.Usages[0].Feature)
+	assert.Equal(t, types.SectionIDType, rp.Usages[0].Section)
+	assert.Equal(t, feature.MutableGlobals, rp.Usages[1].Feature)
+	assert.Equal(t, types.SectionIDImport, rp.Usages[1].Section)
+	assert.Equal(t, feature.SignExtension, rp.Usages[2].Feature)
+	assert.Equal(t, uint32(0), rp.Usages[2].Index)
+	assert.Equal(t, int64(bytes.IndexByte(bs, 0xc0)), rp.Usages[2].Offset)
+	assert.Equal(t, feature.SIMD, rp.Usages[3].Feature)
+	assert.Equal(t, types.SectionIDCustom, rp.Usages[3].Section)
+}
+
+func TestNonCanonicalLEB(t *testing.T) {
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+
+	mod, err := DecodeBytes(buf)
+	assert.Nil(t, err)
+	assert.Empty(t, mod.NonCanonicalLEBs)
+
+	opts := DefaultDecodeOptions()
+	opts.ReportNonCanonicalLEB = true
+
+	mod, err = DecodeBytesWithOptions(buf, opts)
+	assert.Nil(t, err)
+	assert.Empty(t, mod.NonCanonicalLEBs)
+
+	// the size of the type section is 1 padded to 2 bytes
+	padded := module(0x01, 0x81, 0x00, 0x00)
+	mod, err = DecodeBytesWithOptions(padded, opts)
+	assert.Nil(t, err)
+	if assert.Len(t, mod.NonCanonicalLEBs, 1) {
+		assert.Equal(t, &types.NonCanonicalLEB{Offset: 9, Width: 2, MinWidth: 1}, mod.NonCanonicalLEBs[0])
+	}
+
+	streamed, err := DecodeModuleWithOptions(bytes.NewBuffer(padded), opts)
+	assert.Nil(t, err)
+	assert.Equal(t, mod.NonCanonicalLEBs, streamed.NonCanonicalLEBs)
+
+	// the unused bits of a u32 must be zero
+	bs := module(0x01, 0x85, 0x80, 0x80, 0x80, 0x10)
+	mod, err = DecodeBytes(bs)
+	assert.Nil(t, mod)
+	assert.True(t, errors.Is(err, common.ErrIntegerTooLarge))
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	// import of m.\xffx
+	imp := module(0x02, 0x08, 0x01, 0x01, 'm', 0x02, 0xff, 'x', 0x00, 0x00)
+	// custom section named \xc3(
+	custom := module(0x00, 0x03, 0x02, 0xc3, 0x28)
+
+	mod, err := DecodeBytes(imp)
+	assert.Nil(t, mod)
+	assert.True(t, errors.Is(err, common.ErrInvalidUTF8))
+	assert.Contains(t, err.Error(), "offset 0xe")
+
+	mod, err = DecodeModule(bytes.NewBuffer(custom))
+	assert.Nil(t, mod)
+	assert.True(t, errors.Is(err, common.ErrInvalidUTF8))
+	assert.Contains(t, err.Error(), "offset 0xb")
+
+	opts := DefaultDecodeOptions()
+	opts.AllowInvalidUTF8 = true
+
+	mod, err = DecodeBytesWithOptions(imp, opts)
+	assert.Nil(t, err)
+	assert.True(t, mod.SecImport[0].InvalidName)
+	assert.Equal(t, "\xffx", mod.SecImport[0].Name)
+
+	mod, err = DecodeBytesWithOptions(custom, opts)
+	assert.Nil(t, err)
+	assert.True(t, mod.SecCustom.InvalidName)
+
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+	mod, err = DecodeBytes(buf)
+	assert.Nil(t, err)
+	for _, exp := range mod.SecExport {
+		assert.False(t, exp.InvalidName)
+	}
+}
+
+func TestSafeName(t *testing.T) {
+	assert.Equal(t, "memory", types.SafeName("memory"))
+	assert.Equal(t, "héllo 世界", types.SafeName("héllo 世界"))
+	assert.Equal(t, `\u001b[31mred`, types.SafeName("\x1b[31mred"))
+	assert.Equal(t, `a\u202eb`, types.SafeName("a\u202eb"))
+	assert.Equal(t, `\xff\\n`, types.SafeName("\xff\\n"))
+	assert.Equal(t, `line\u000a`, types.SafeName("line\n"))
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+
+	// cutting the module in the middle of a section must not be taken as its end
+	for _, n := range []int{9, 20, len(buf) / 2, len(buf) - 1} {
+		_, err = DecodeModule(bytes.NewReader(buf[:n]))
+		assert.True(t, err != nil && !errors.Is(err, io.EOF), "stream, %d bytes: %v", n, err)
+
+		_, err = DecodeModule(iotest.OneByteReader(bytes.NewReader(buf[:n])))
+		assert.True(t, err != nil && !errors.Is(err, io.EOF), "buffered stream, %d bytes: %v", n, err)
+
+		_, err = DecodeBytes(buf[:n])
+		assert.True(t, err != nil && !errors.Is(err, io.EOF), "bytes, %d bytes: %v", n, err)
+
+		_, err = DecodeReaderAt(bytes.NewReader(buf[:n]), int64(n))
+		assert.True(t, err != nil && !errors.Is(err, io.EOF), "lazy, %d bytes: %v", n, err)
+
+		var de *types.DecodeError
+		if assert.True(t, errors.As(err, &de)) {
+			assert.True(t, de.Offset > 0 && de.Offset <= int64(n), "offset of %d bytes: %#x", n, de.Offset)
+		}
+	}
+}
+
+func TestValueType(t *testing.T) {
+	assert.Equal(t, "i32", types.ValueTypeI32.String())
+	assert.Equal(t, "externref", types.ValueTypeExternRef.String())
+	assert.Equal(t, "valtype(0x40)", types.ValueType(0x40).String())
+	assert.Equal(t, byte(0x7c), types.ValueTypeF64.Code())
+
+	assert.True(t, types.ValueTypeF64.IsNumeric())
+	assert.False(t, types.ValueTypeV128.IsNumeric())
+	assert.True(t, types.ValueTypeV128.IsVector())
+	assert.True(t, types.ValueTypeFuncRef.IsReference())
+	assert.Equal(t, types.HeapTypeExtern, types.ValueTypeExternRef.HeapType())
+
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+	mod, err := DecodeBytes(buf)
+	assert.Nil(t, err)
+	for _, ft := range mod.SecType {
+		for _, vt := range append(append([]types.ValueType{}, ft.InputType...), ft.ReturnType...) {
+			assert.True(t, vt.IsNumeric(), vt.String())
+		}
+	}
+}
+
+func TestKeepRawSections(t *testing.T) {
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+
+	mod, err := DecodeBytes(buf)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, mod.Sections)
+	for _, s := range mod.Sections {
+		assert.Nil(t, s.Raw)
+	}
+
+	opts := DefaultDecodeOptions()
+	opts.KeepRawSections = true
+
+	decoders := map[string]func() (*types.Module, error){
+		"stream": func() (*types.Module, error) { return DecodeModuleWithOptions(bytes.NewBuffer(buf), opts) },
+		"bytes":  func() (*types.Module, error) { return DecodeBytesWithOptions(buf, opts) },
+		"lazy": func() (*types.Module, error) {
+			return DecodeReaderAtWithOptions(bytes.NewReader(buf), int64(len(buf)), opts)
+		},
+	}
+
+	for name, decode := range decoders {
+		t.Run(name, func(t *testing.T) {
+			mod, err := decode()
+			assert.Nil(t, err)
+
+			// the header and the raw sections make up the module binary
+			out := append([]byte{}, buf[:8]...)
+			for _, s := range mod.Sections {
+				raw, err := s.LoadRaw()
+				assert.Nil(t, err)
+				assert.Equal(t, byte(s.ID), raw[0])
+				assert.Equal(t, int(s.End()-s.Offset), len(raw))
+				out = append(out, raw...)
+			}
+			assert.Equal(t, buf, out)
+
+			code := mod.SectionAt(mod.SecCode[0].BodyOffset)
+			if assert.NotNil(t, code) {
+				assert.Equal(t, types.SectionIDCode, code.ID)
+			}
+			assert.Nil(t, mod.SectionAt(int64(len(buf))))
+		})
+	}
+
+	// the size of the type section is 1 padded to 2 bytes
+	padded := module(0x01, 0x81, 0x00, 0x00)
+	mod, err = DecodeBytesWithOptions(padded, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, &types.SectionInfo{
+		ID:            types.SectionIDType,
+		Offset:        8,
+		PayloadOffset: 11,
+		Size:          1,
+		Raw:           padded[8:],
+	}, mod.Sections[0])
+	assert.Equal(t, 2, mod.Sections[0].SizeWidth())
+	assert.Len(t, mod.NonCanonicalLEBs, 1)
+
+	// the payload must take the declared size
+	_, err = DecodeBytes(module(0x01, 0x02, 0x00, 0x00))
+	assert.Error(t, err)
+}
+
+func TestCustomSectionDecoders(t *testing.T) {
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+
+	mod, err := DecodeBytes(buf)
+	assert.Nil(t, err)
+	if ns := mod.NameSection(); assert.NotNil(t, ns) {
+		assert.Equal(t, "main", ns.Functions[1])
+	}
+
+	// a nil decoder leaves the section undecoded
+	opts := DefaultDecodeOptions()
+	opts.CustomSectionDecoders = map[string]types.CustomSectionDecoder{"name": nil}
+	mod, err = DecodeBytesWithOptions(buf, opts)
+	assert.Nil(t, err)
+	assert.Nil(t, mod.NameSection())
+	assert.NotNil(t, mod.CustomSection("name"))
+
+	custom := func(name string, payload ...byte) []byte {
+		b := append([]byte{byte(len(name))}, name...)
+		b = append(b, payload...)
+		return append([]byte{0x00, byte(len(b))}, b...)
+	}
+	bs := module(custom("producers",
+		0x01, 0x08, 'l', 'a', 'n', 'g', 'u', 'a', 'g', 'e', 0x01, 0x04, 'R', 'u', 's', 't', 0x04, '1', '.', '5', '6')...)
+	bs = append(bs, custom("target_features", 0x02, '+', 0x04, 's', 'i', 'm', 'd', '-', 0x07, 'a', 't', 'o', 'm', 'i', 'c', 's')...)
+	bs = append(bs, custom("acme.meta", 0x2a)...)
+
+	mod, err = DecodeBytes(bs)
+	assert.Nil(t, err)
+	assert.Equal(t, []types.ProducerValue{{Name: "Rust", Version: "1.56"}}, mod.ProducersSection().Field("language"))
+	assert.Equal(t, []types.TargetFeature{{Prefix: '+', Name: "simd"}, {Prefix: '-', Name: "atomics"}},
+		mod.TargetFeaturesSection().Features)
+	assert.Nil(t, mod.CustomSection("acme.meta").Value)
+
+	// in-house sections are decoded by registered decoders
+	types.RegisterCustomSectionDecoder("acme.meta", func(data []byte) (interface{}, error) {
+		return int(data[0]), nil
+	})
+	defer types.RegisterCustomSectionDecoder("acme.meta", nil)
+
+	mod, err = DecodeBytes(bs)
+	assert.Nil(t, err)
+	assert.Equal(t, 42, mod.CustomSection("acme.meta").Value)
+
+	// errors of decoders are kept unless StrictCustomSections is set
+	errMeta := errors.New("bad metadata")
+	opts = DefaultDecodeOptions()
+	opts.CustomSectionDecoders = map[string]types.CustomSectionDecoder{
+		"acme.meta": func(data []byte) (interface{}, error) { return nil, errMeta },
+	}
+	mod, err = DecodeBytesWithOptions(bs, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, errMeta, mod.CustomSection("acme.meta").Err)
+	assert.NotNil(t, mod.ProducersSection())
+
+	opts.StrictCustomSections = true
+	_, err = DecodeBytesWithOptions(bs, opts)
+	assert.True(t, errors.Is(err, errMeta))
+}
+
+func TestUnknownSections(t *testing.T) {
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+	unknown := []byte{0x0d, 0x03, 0x01, 0x02, 0x03}
+	bs := append(append([]byte{}, buf...), unknown...)
+
+	_, err = DecodeBytes(bs)
+	assert.True(t, errors.Is(err, common.ErrUnknownSection))
+	var unknownErr *types.UnknownSectionError
+	if assert.True(t, errors.As(err, &unknownErr)) {
+		assert.Equal(t, &types.UnknownSectionError{ID: 13, Offset: int64(len(buf)), Size: 3}, unknownErr)
+	}
+
+	opts := &DecodeOptions{AllowUnknownSections: true}
+	decoders := map[string]func() (*types.Module, error){
+		"stream": func() (*types.Module, error) { return DecodeModuleWithOptions(bytes.NewBuffer(bs), opts) },
+		"bytes":  func() (*types.Module, error) { return DecodeBytesWithOptions(bs, opts) },
+		"lazy": func() (*types.Module, error) {
+			return DecodeReaderAtWithOptions(bytes.NewReader(bs), int64(len(bs)), opts)
+		},
+	}
+
+	for name, decode := range decoders {
+		t.Run(name, func(t *testing.T) {
+			mod, err := decode()
+			assert.Nil(t, err)
+			if assert.Len(t, mod.UnknownSections, 1) {
+				s := mod.UnknownSections[0]
+				assert.Equal(t, types.SectionID(13), s.ID)
+				assert.Equal(t, int64(len(buf)), s.Offset)
+				raw, err := s.LoadRaw()
+				assert.Nil(t, err)
+				assert.Equal(t, unknown, raw)
+			}
+			// sections of known ids are not kept
+			assert.Nil(t, mod.Sections[0].Raw)
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	bs := module(
+		0x01, 0x04, 0x01, 0x60, 0x00, 0x00, // type section
+		0x05, 0x03, 0x01, 0xff, 0x00, // memory section of invalid limits
+		0x03, 0x04, 0x03, 0x00, 0x00, 0x00, // function section
+		0x0a, 0x0b, 0x03, // code section, the second segment declares a local of invalid type
+		0x02, 0x00, 0x0b,
+		0x03, 0x01, 0x05, 0x7a,
+		0x02, 0x00, 0x0b,
+		0x0e, 0x01, 0x00, // unknown section
+	)
+
+	_, err := DecodeBytes(bs)
+	assert.Error(t, err)
+
+	opts := DefaultDecodeOptions()
+	opts.Recover = true
+	opts.KeepRawSections = true
+
+	decoders := map[string]func(bs []byte) (*types.Module, error){
+		"stream": func(bs []byte) (*types.Module, error) { return DecodeModuleWithOptions(bytes.NewBuffer(bs), opts) },
+		"bytes":  func(bs []byte) (*types.Module, error) { return DecodeBytesWithOptions(bs, opts) },
+		"lazy": func(bs []byte) (*types.Module, error) {
+			return DecodeReaderAtWithOptions(bytes.NewReader(bs), int64(len(bs)), opts)
+		},
+	}
+
+	for name, decode := range decoders {
+		t.Run(name, func(t *testing.T) {
+			mod, err := decode(bs)
+			assert.Nil(t, err)
+
+			assert.Len(t, mod.SecType, 1)
+			assert.Empty(t, mod.SecMemory)
+			assert.Len(t, mod.SecFunction, 3)
+			if assert.Len(t, mod.SecCode, 3) {
+				assert.NotNil(t, mod.SecCode[0])
+				assert.Nil(t, mod.SecCode[1])
+				assert.NotNil(t, mod.SecCode[2])
+			}
+
+			if assert.Len(t, mod.Diagnostics, 3) {
+				assert.Equal(t, types.SectionIDMemory, mod.Diagnostics[0].Section)
+				assert.Equal(t, int64(18), mod.Diagnostics[0].Offset)
+				assert.Equal(t, types.SectionIDCode, mod.Diagnostics[1].Section)
+				assert.Equal(t, int64(35), mod.Diagnostics[1].Offset)
+				assert.Equal(t, types.SectionID(0x0e), mod.Diagnostics[2].Section)
+				assert.Equal(t, int64(38), mod.Diagnostics[2].Offset)
+				assert.True(t, errors.Is(mod.Diagnostics[2], common.ErrUnknownSection))
+			}
+			assert.Len(t, mod.UnknownSections, 1)
+
+			// the skipped sections are kept as well
+			out := append([]byte{}, header...)
+			for _, s := range mod.Sections {
+				raw, err := s.LoadRaw()
+				assert.Nil(t, err)
+				out = append(out, raw...)
+			}
+			assert.Equal(t, bs, out)
+
+			// a truncated section ends the module
+			mod, err = decode(bs[:len(bs)-6])
+			assert.Nil(t, err)
+			assert.Len(t, mod.SecCode, 2)
+			if assert.Len(t, mod.Diagnostics, 3) {
+				assert.True(t, errors.Is(mod.Diagnostics[2], io.ErrUnexpectedEOF))
+			}
+		})
+	}
+}
+
+func TestDecodeContext(t *testing.T) {
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mod, err := DecodeModuleContext(ctx, bytes.NewBuffer(buf), nil)
+	assert.Nil(t, mod)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	// the progress goes up to the size of the file
+	var reads []int64
+	opts := DefaultDecodeOptions()
+	opts.Progress = func(id types.SectionID, read int64, total int64) {
+		assert.Equal(t, int64(len(buf)), total)
+		reads = append(reads, read)
+	}
+	mod, err = DecodeFileContext(context.Background(), fileName, opts)
+	assert.Nil(t, err)
+	assert.NotNil(t, mod)
+	assert.True(t, len(reads) >= len(mod.Sections)+len(mod.SecCode))
+	for i := 1; i < len(reads); i++ {
+		assert.True(t, reads[i-1] <= reads[i])
+	}
+	assert.Equal(t, int64(len(buf)), reads[len(reads)-1])
+
+	// the size of a stream is unknown
+	opts.Progress = func(id types.SectionID, read int64, total int64) {
+		assert.Equal(t, int64(-1), total)
+	}
+	_, err = DecodeModuleContext(context.Background(), bytes.NewBuffer(buf), opts)
+	assert.Nil(t, err)
+
+	// canceled between code segments, which is not recovered
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	opts.Recover = true
+	opts.Progress = func(id types.SectionID, read int64, total int64) {
+		if id == types.SectionIDCode {
+			cancel()
+		}
+	}
+	mod, err = DecodeModuleContext(ctx, bytes.NewBuffer(buf), opts)
+	assert.Nil(t, mod)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,179 @@
+package decode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/params"
+	"github.com/LBruyne/wasm-decode/types"
+	"io/ioutil"
+	"sort"
+)
+
+// Encoding tells how a module is embedded in another file
+type Encoding int
+
+const (
+	// EncodingRaw is the module binary as it is, e.g. in an executable
+	EncodingRaw Encoding = iota
+	// EncodingBase64 is a base64 string, e.g. in the JavaScript emitted by wasm-pack
+	EncodingBase64
+	// EncodingDataURL is a base64 data URL such as data:application/wasm;base64,...
+	EncodingDataURL
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case EncodingRaw:
+		return "raw"
+	case EncodingBase64:
+		return "base64"
+	case EncodingDataURL:
+		return "data URL"
+	}
+	return fmt.Sprintf("encoding(%d)", int(e))
+}
+
+// Embedded is a module found in another file
+type Embedded struct {
+	Encoding Encoding
+	Offset   int64 // offset of the module in the scanned file, after the prefix of a data URL
+	Length   int64 // length of the module in the scanned file, as it is encoded
+
+	Module *types.Module // nil if the module fails to decode
+	Err    error
+}
+
+var (
+	// moduleHeader starts every module binary
+	moduleHeader = append(append([]byte{}, params.MagicNumber...), params.Version...)
+	// base64Header starts the base64 encoding of every module binary, with either alphabet
+	base64Header = []byte(base64.StdEncoding.EncodeToString(moduleHeader[:6]))
+	// dataURLBase64 precedes the payload of a base64 data URL
+	dataURLBase64 = []byte(";base64,")
+)
+
+// ExtractFile reads the file fn and extracts the modules embedded in it, see Extract
+func ExtractFile(fn string, opts *DecodeOptions) ([]*Embedded, error) {
+	bs, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, fmt.Errorf("read file %v: %w", fn, err)
+	}
+	return Extract(bs, opts), nil
+}
+
+// Extract finds the modules embedded in b, such as the base64 strings and data URLs of
+// .js and .html files, and the module binaries in arbitrary files, and decodes each one according to opts.
+// Only the candidates starting with the magic number and the version are kept, in the order of b.
+//
+// The end of a raw module is guessed from its section headers: it ends before a section of invalid id,
+// out of order or exceeding b.
+func Extract(b []byte, opts *DecodeOptions) []*Embedded {
+	ret := append(extractBase64(b, opts), extractRaw(b, opts)...)
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Offset < ret[j].Offset })
+	return ret
+}
+
+// extractBase64 finds the modules encoded in base64 strings and data URLs
+func extractBase64(b []byte, opts *DecodeOptions) []*Embedded {
+	var ret []*Embedded
+	for off := 0; ; {
+		i := bytes.Index(b[off:], base64Header)
+		if i < 0 {
+			return ret
+		}
+		start := off + i
+		end := start
+		for end < len(b) && isBase64Char(b[end]) {
+			end++
+		}
+		off = end
+
+		bin, err := decodeBase64(b[start:end])
+		if err != nil || !bytes.HasPrefix(bin, moduleHeader) {
+			continue
+		}
+
+		e := &Embedded{Encoding: EncodingBase64, Offset: int64(start), Length: int64(end - start)}
+		if bytes.HasSuffix(b[:start], dataURLBase64) {
+			e.Encoding = EncodingDataURL
+		}
+		e.Module, e.Err = DecodeBytesWithOptions(bin, opts)
+		ret = append(ret, e)
+	}
+}
+
+// extractRaw finds the module binaries, including the ones inside another module
+func extractRaw(b []byte, opts *DecodeOptions) []*Embedded {
+	var ret []*Embedded
+	for off := 0; ; {
+		i := bytes.Index(b[off:], moduleHeader)
+		if i < 0 {
+			return ret
+		}
+		start := off + i
+		off = start + len(moduleHeader)
+
+		n := moduleLength(b[start:])
+		e := &Embedded{Encoding: EncodingRaw, Offset: int64(start), Length: int64(n)}
+		e.Module, e.Err = DecodeBytesWithOptions(b[start:start+n], opts)
+		ret = append(ret, e)
+	}
+}
+
+// sectionOrder is the rank of each section id which may appear once, in the order of the binary format
+var sectionOrder = map[types.SectionID]int{
+	types.SectionIDType:      1,
+	types.SectionIDImport:    2,
+	types.SectionIDFunction:  3,
+	types.SectionIDTable:     4,
+	types.SectionIDMemory:    5,
+	types.SectionIDGlobal:    6,
+	types.SectionIDExport:    7,
+	types.SectionIDStart:     8,
+	types.SectionIDElement:   9,
+	types.SectionIDDataCount: 10,
+	types.SectionIDCode:      11,
+	types.SectionIDData:      12,
+}
+
+// moduleLength returns the length of the module at the start of b, which may be followed by other bytes
+func moduleLength(b []byte) int {
+	off, last := len(moduleHeader), 0
+	for off < len(b) {
+		id := types.SectionID(b[off])
+		if id != types.SectionIDCustom {
+			rank, ok := sectionOrder[id]
+			if !ok || rank <= last {
+				break
+			}
+			last = rank
+		}
+
+		size, n, err := common.DecodeUint32Bytes(b[off+1:])
+		if err != nil || int64(size) > int64(len(b)-off-1-n) {
+			break
+		}
+		off += 1 + n + int(size)
+	}
+	return off
+}
+
+func isBase64Char(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+		c == '+' || c == '/' || c == '-' || c == '_' || c == '='
+}
+
+// decodeBase64 decodes s of the standard or the URL alphabet, with or without padding
+func decodeBase64(s []byte) ([]byte, error) {
+	s = bytes.TrimRight(s, "=")
+	enc := base64.RawStdEncoding
+	if bytes.ContainsAny(s, "-_") {
+		enc = base64.RawURLEncoding
+	}
+
+	buf := make([]byte, enc.DecodedLen(len(s)))
+	n, err := enc.Decode(buf, s)
+	return buf[:n], err
+}
@@ -0,0 +1,57 @@
+package decode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	buf, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+	b64 := base64.StdEncoding.EncodeToString(buf)
+	url := base64.RawURLEncoding.EncodeToString(buf)
+
+	js := `var wasm = "` + b64 + `"; var other = "AGFzbQEAxx";` +
+		`<script src="data:application/wasm;base64,` + url + `"></script>`
+	found := Extract([]byte(js), nil)
+	if assert.Len(t, found, 2) {
+		assert.Equal(t, EncodingBase64, found[0].Encoding)
+		assert.Equal(t, int64(len(`var wasm = "`)), found[0].Offset)
+		assert.Equal(t, int64(len(b64)), found[0].Length)
+
+		assert.Equal(t, EncodingDataURL, found[1].Encoding)
+		assert.Equal(t, int64(len(url)), found[1].Length)
+
+		for _, e := range found {
+			assert.Nil(t, e.Err)
+			assert.NotEmpty(t, e.Module.SecCode)
+		}
+	}
+
+	// a module binary is followed by other bytes
+	bin := append(append([]byte("\x7fELF\x00asm\x02\x00\x00\x00"), buf...), 0xff, 0x00, 0x61, 0x73)
+	found = Extract(bin, nil)
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, EncodingRaw, found[0].Encoding)
+		assert.Equal(t, int64(12), found[0].Offset)
+		assert.Equal(t, int64(len(buf)), found[0].Length)
+		assert.Nil(t, found[0].Err)
+		assert.NotNil(t, found[0].Module)
+	}
+
+	// the last section of a truncated module is taken for other bytes
+	mod, err := DecodeBytes(buf)
+	assert.Nil(t, err)
+	last := mod.Sections[len(mod.Sections)-1]
+	found = Extract(buf[:len(buf)-1], nil)
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, last.Offset, found[0].Length)
+		assert.Nil(t, found[0].Err)
+		assert.Len(t, found[0].Module.Sections, len(mod.Sections)-1)
+	}
+
+	assert.Empty(t, Extract(bytes.Repeat([]byte("AGFzbQEA"), 4), nil))
+}
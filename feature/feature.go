@@ -0,0 +1,127 @@
+package feature
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Feature is a post-MVP proposal of WASM, each one takes a single bit
+type Feature uint64
+
+const (
+	SignExtension Feature = 1 << iota
+	NonTrappingFloatToInt
+	MutableGlobals
+	MultiValue
+	BulkMemory
+	ReferenceTypes
+	SIMD
+	Threads
+	Memory64
+	MultiMemory
+	TailCall
+	ExtendedConst // only reported, the decoding accepts no constant expression of several instructions
+	ExceptionHandling
+)
+
+var names = map[Feature]string{
+	SignExtension:         "sign-extension",
+	NonTrappingFloatToInt: "nontrapping-fptoint",
+	MutableGlobals:        "mutable-globals",
+	MultiValue:            "multi-value",
+	BulkMemory:            "bulk-memory",
+	ReferenceTypes:        "reference-types",
+	SIMD:                  "simd",
+	Threads:               "threads",
+	Memory64:              "memory64",
+	MultiMemory:           "multi-memory",
+	TailCall:              "tail-call",
+	ExtendedConst:         "extended-const",
+	ExceptionHandling:     "exception-handling",
+}
+
+// List returns all the known features in the order of their bits
+func List() []Feature {
+	ret := make([]Feature, 0, len(names))
+	for f := SignExtension; f <= ExceptionHandling; f <<= 1 {
+		ret = append(ret, f)
+	}
+	return ret
+}
+
+func (f Feature) String() string {
+	if n, ok := names[f]; ok {
+		return n
+	}
+	return fmt.Sprintf("feature(%#x)", uint64(f))
+}
+
+// Parse returns the feature of the given name, e.g. "bulk-memory"
+func Parse(name string) (Feature, error) {
+	for f, n := range names {
+		if n == name {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown feature: %s", name)
+}
+
+// Set is a set of features
+type Set uint64
+
+const (
+	// MVP is WASM 1.0 without any proposal
+	MVP Set = 0
+
+	// Wasm20 is the set of proposals merged into WASM 2.0
+	Wasm20 = Set(SignExtension | NonTrappingFloatToInt | MutableGlobals | MultiValue |
+		BulkMemory | ReferenceTypes | SIMD)
+
+	// All contains all the known features
+	All = Set(ExceptionHandling<<1 - 1)
+)
+
+// Has reports whether f is in the set
+func (s Set) Has(f Feature) bool {
+	return s&Set(f) != 0
+}
+
+// With returns the set with fs added
+func (s Set) With(fs ...Feature) Set {
+	for _, f := range fs {
+		s |= Set(f)
+	}
+	return s
+}
+
+// Without returns the set with fs removed
+func (s Set) Without(fs ...Feature) Set {
+	for _, f := range fs {
+		s &^= Set(f)
+	}
+	return s
+}
+
+// List returns the features in the set in the order of their bits
+func (s Set) List() []Feature {
+	var ret []Feature
+	for _, f := range List() {
+		if s.Has(f) {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
+
+func (s Set) String() string {
+	if s == MVP {
+		return "mvp"
+	}
+
+	fs := s.List()
+	ns := make([]string, len(fs))
+	for i, f := range fs {
+		ns[i] = f.String()
+	}
+	return strings.Join(ns, ",")
+}
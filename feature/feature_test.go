@@ -0,0 +1,34 @@
+package feature
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	s := MVP.With(BulkMemory, SIMD)
+	assert.True(t, s.Has(BulkMemory))
+	assert.True(t, s.Has(SIMD))
+	assert.False(t, s.Has(Threads))
+	assert.Equal(t, "bulk-memory,simd", s.String())
+
+	s = s.Without(SIMD)
+	assert.False(t, s.Has(SIMD))
+	assert.Equal(t, "mvp", MVP.String())
+
+	assert.Equal(t, len(List()), len(All.List()))
+	assert.True(t, All.Has(ExceptionHandling))
+	assert.True(t, Wasm20.Has(ReferenceTypes))
+	assert.False(t, Wasm20.Has(Memory64))
+}
+
+func TestParse(t *testing.T) {
+	for _, f := range List() {
+		got, err := Parse(f.String())
+		assert.Nil(t, err)
+		assert.Equal(t, f, got)
+	}
+
+	_, err := Parse("gc")
+	assert.Error(t, err)
+}
@@ -0,0 +1,348 @@
+package operator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/LBruyne/wasm-decode/common"
+	"math"
+)
+
+// Label refers to an open block, loop or if, or to the function body, in the branches
+type Label int
+
+// BodyLabel is the label of the function body, a branch to it returns from the function
+const BodyLabel Label = 0
+
+// BlockType is the type of a block, loop or if, in the s33 encoding of the binary format
+type BlockType int64
+
+// BlockVoid is the block type of blocks without result
+const BlockVoid = BlockType(int64(BlockTypeEmpty) - 0x80)
+
+// BlockResult returns the block type of blocks with a result of the value type of code, e.g. 0x7f for i32
+func BlockResult(code byte) BlockType {
+	return BlockType(int64(code) - 0x80)
+}
+
+// BlockFunc returns the block type of blocks of the function type at typeIdx
+func BlockFunc(typeIdx uint32) BlockType {
+	return BlockType(typeIdx)
+}
+
+// MemArg is the memory argument of loads and stores, Align is the log2 of the alignment
+type MemArg struct {
+	Align  uint32
+	Offset uint64
+	MemIdx uint32 // defined by multi-memory proposal
+}
+
+// Assembler assembles the body of a function instruction by instruction.
+// The blocks are referred by the labels returned when they are opened,
+// which the branches resolve into relative depths.
+//
+// Errors are kept until Assemble, so that the calls can be chained.
+// The SIMD and atomic instructions are not covered.
+type Assembler struct {
+	buf    []byte
+	blocks []openBlock // the function body comes first
+	labels Label
+	err    error
+}
+
+type openBlock struct {
+	label  Label
+	op     OpCode
+	inElse bool
+}
+
+// NewAssembler returns an assembler of an empty function body
+func NewAssembler() *Assembler {
+	return &Assembler{blocks: []openBlock{{label: BodyLabel}}}
+}
+
+// fail records the first error
+func (a *Assembler) fail(err error) {
+	if a.err == nil {
+		a.err = fmt.Errorf("assemble %v-th byte: %w", len(a.buf), err)
+	}
+}
+
+func (a *Assembler) op(op OpCode) *Assembler {
+	a.buf = append(a.buf, byte(op))
+	return a
+}
+
+func (a *Assembler) uint32s(op OpCode, imms ...uint32) *Assembler {
+	a.op(op)
+	for _, imm := range imms {
+		a.buf = append(a.buf, common.EncodeUint32(imm)...)
+	}
+	return a
+}
+
+func (a *Assembler) misc(sub uint32, imms ...uint32) *Assembler {
+	return a.uint32s(OpCodePrefixMisc, append([]uint32{sub}, imms...)...)
+}
+
+func (a *Assembler) memArg(op OpCode, m MemArg) *Assembler {
+	a.op(op)
+	if m.MemIdx != 0 {
+		a.buf = append(a.buf, common.EncodeUint32(m.Align|MemArgMemIdxFlag)...)
+		a.buf = append(a.buf, common.EncodeUint32(m.MemIdx)...)
+	} else {
+		a.buf = append(a.buf, common.EncodeUint32(m.Align)...)
+	}
+	a.buf = append(a.buf, common.EncodeUint64(m.Offset)...)
+	return a
+}
+
+// open opens a block of op and returns its label
+func (a *Assembler) open(op OpCode, bt BlockType) Label {
+	a.op(op)
+	a.buf = append(a.buf, common.EncodeInt64(int64(bt))...)
+	a.labels++
+	a.blocks = append(a.blocks, openBlock{label: a.labels, op: op})
+	return a.labels
+}
+
+// depth returns the relative depth of the block of l, which must be open
+func (a *Assembler) depth(l Label) uint32 {
+	for i := len(a.blocks) - 1; i >= 0; i-- {
+		if a.blocks[i].label == l {
+			return uint32(len(a.blocks) - 1 - i)
+		}
+	}
+	a.fail(fmt.Errorf("%w: %d is not open", common.ErrUnknownLabel, l))
+	return 0
+}
+
+// Block opens a block and returns its label, a branch to it jumps to its end
+func (a *Assembler) Block(bt BlockType) Label {
+	return a.open(OpCodeBlock, bt)
+}
+
+// Loop opens a loop and returns its label, a branch to it jumps to its beginning
+func (a *Assembler) Loop(bt BlockType) Label {
+	return a.open(OpCodeLoop, bt)
+}
+
+// If opens an if, which pops its condition, and returns its label
+func (a *Assembler) If(bt BlockType) Label {
+	return a.open(OpCodeIf, bt)
+}
+
+// Else starts the else branch of the innermost block, which must be an if
+func (a *Assembler) Else() *Assembler {
+	top := &a.blocks[len(a.blocks)-1]
+	if top.op != OpCodeIf || top.inElse {
+		a.fail(fmt.Errorf("%w: else without if", common.ErrUnbalancedBlock))
+	}
+	top.inElse = true
+	return a.op(OpCodeElse)
+}
+
+// End closes the innermost block, the end of the function body is appended by Assemble
+func (a *Assembler) End() *Assembler {
+	if len(a.blocks) == 1 {
+		a.fail(fmt.Errorf("%w: end without block", common.ErrUnbalancedBlock))
+		return a
+	}
+	a.blocks = a.blocks[:len(a.blocks)-1]
+	return a.op(OpCodeEnd)
+}
+
+// Br branches to the block of l
+func (a *Assembler) Br(l Label) *Assembler {
+	return a.uint32s(OpCodeBr, a.depth(l))
+}
+
+// BrIf branches to the block of l if the condition it pops is not zero
+func (a *Assembler) BrIf(l Label) *Assembler {
+	return a.uint32s(OpCodeBrIf, a.depth(l))
+}
+
+// BrTable branches to the block of the label at the index it pops, or to the block of def
+// if the index is out of range
+func (a *Assembler) BrTable(labels []Label, def Label) *Assembler {
+	depths := []uint32{uint32(len(labels))}
+	for _, l := range labels {
+		depths = append(depths, a.depth(l))
+	}
+	return a.uint32s(OpCodeBrTable, append(depths, a.depth(def))...)
+}
+
+// Unreachable appends unreachable
+func (a *Assembler) Unreachable() *Assembler { return a.op(OpCodeUnreachable) }
+
+// Nop appends nop
+func (a *Assembler) Nop() *Assembler { return a.op(OpCodeNop) }
+
+// Return appends return
+func (a *Assembler) Return() *Assembler { return a.op(OpCodeReturn) }
+
+// Call calls the function at funcIdx
+func (a *Assembler) Call(funcIdx uint32) *Assembler { return a.uint32s(OpCodeCall, funcIdx) }
+
+// CallIndirect calls the function of the type at typeIdx in the table at tableIdx
+func (a *Assembler) CallIndirect(typeIdx, tableIdx uint32) *Assembler {
+	return a.uint32s(OpCodeCallIndirect, typeIdx, tableIdx)
+}
+
+// ReturnCall tail-calls the function at funcIdx
+func (a *Assembler) ReturnCall(funcIdx uint32) *Assembler {
+	return a.uint32s(OpCodeReturnCall, funcIdx)
+}
+
+// ReturnCallIndirect tail-calls the function of the type at typeIdx in the table at tableIdx
+func (a *Assembler) ReturnCallIndirect(typeIdx, tableIdx uint32) *Assembler {
+	return a.uint32s(OpCodeReturnCallIndirect, typeIdx, tableIdx)
+}
+
+// Drop appends drop
+func (a *Assembler) Drop() *Assembler { return a.op(OpCodeDrop) }
+
+// Select appends select
+func (a *Assembler) Select() *Assembler { return a.op(OpCodeSelect) }
+
+// SelectTyped appends select with the value types of codes
+func (a *Assembler) SelectTyped(codes ...byte) *Assembler {
+	a.uint32s(OpCodeSelectTyped, uint32(len(codes)))
+	a.buf = append(a.buf, codes...)
+	return a
+}
+
+// LocalGet appends local.get of the local at idx
+func (a *Assembler) LocalGet(idx uint32) *Assembler { return a.uint32s(OpCodeLocalGet, idx) }
+
+// LocalSet appends local.set of the local at idx
+func (a *Assembler) LocalSet(idx uint32) *Assembler { return a.uint32s(OpCodeLocalSet, idx) }
+
+// LocalTee appends local.tee of the local at idx
+func (a *Assembler) LocalTee(idx uint32) *Assembler { return a.uint32s(OpCodeLocalTee, idx) }
+
+// GlobalGet appends global.get of the global at idx
+func (a *Assembler) GlobalGet(idx uint32) *Assembler { return a.uint32s(OpCodeGlobalGet, idx) }
+
+// GlobalSet appends global.set of the global at idx
+func (a *Assembler) GlobalSet(idx uint32) *Assembler { return a.uint32s(OpCodeGlobalSet, idx) }
+
+// TableGet appends table.get of the table at idx
+func (a *Assembler) TableGet(idx uint32) *Assembler { return a.uint32s(OpCodeTableGet, idx) }
+
+// TableSet appends table.set of the table at idx
+func (a *Assembler) TableSet(idx uint32) *Assembler { return a.uint32s(OpCodeTableSet, idx) }
+
+// MemorySize appends memory.size of the memory at idx
+func (a *Assembler) MemorySize(idx uint32) *Assembler { return a.uint32s(OpCodeMemorySize, idx) }
+
+// MemoryGrow appends memory.grow of the memory at idx
+func (a *Assembler) MemoryGrow(idx uint32) *Assembler { return a.uint32s(OpCodeMemoryGrow, idx) }
+
+// I32Const appends i32.const v
+func (a *Assembler) I32Const(v int32) *Assembler {
+	a.op(OpCodeI32Const)
+	a.buf = append(a.buf, common.EncodeInt32(v)...)
+	return a
+}
+
+// I64Const appends i64.const v
+func (a *Assembler) I64Const(v int64) *Assembler {
+	a.op(OpCodeI64Const)
+	a.buf = append(a.buf, common.EncodeInt64(v)...)
+	return a
+}
+
+// F32Const appends f32.const v
+func (a *Assembler) F32Const(v float32) *Assembler {
+	a.op(OpCodeF32Const)
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], math.Float32bits(v))
+	a.buf = append(a.buf, b[:]...)
+	return a
+}
+
+// F64Const appends f64.const v
+func (a *Assembler) F64Const(v float64) *Assembler {
+	a.op(OpCodeF64Const)
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+	a.buf = append(a.buf, b[:]...)
+	return a
+}
+
+// RefNull appends ref.null of the reference type of code, e.g. 0x70 for funcref
+func (a *Assembler) RefNull(code byte) *Assembler {
+	a.buf = append(a.buf, byte(OpCodeRefNull), code)
+	return a
+}
+
+// RefIsNull appends ref.is_null
+func (a *Assembler) RefIsNull() *Assembler { return a.op(OpCodeRefIsNull) }
+
+// RefFunc appends ref.func of the function at funcIdx
+func (a *Assembler) RefFunc(funcIdx uint32) *Assembler { return a.uint32s(OpCodeRefFunc, funcIdx) }
+
+// MemoryInit copies the data segment at dataIdx into the memory at memIdx
+func (a *Assembler) MemoryInit(dataIdx, memIdx uint32) *Assembler {
+	return a.misc(OpCodeMiscMemoryInit, dataIdx, memIdx)
+}
+
+// DataDrop drops the data segment at dataIdx
+func (a *Assembler) DataDrop(dataIdx uint32) *Assembler {
+	return a.misc(OpCodeMiscDataDrop, dataIdx)
+}
+
+// MemoryCopy copies from the memory at src into the memory at dst
+func (a *Assembler) MemoryCopy(dst, src uint32) *Assembler {
+	return a.misc(OpCodeMiscMemoryCopy, dst, src)
+}
+
+// MemoryFill fills the memory at memIdx
+func (a *Assembler) MemoryFill(memIdx uint32) *Assembler {
+	return a.misc(OpCodeMiscMemoryFill, memIdx)
+}
+
+// TableInit copies the element segment at elemIdx into the table at tableIdx
+func (a *Assembler) TableInit(elemIdx, tableIdx uint32) *Assembler {
+	return a.misc(OpCodeMiscTableInit, elemIdx, tableIdx)
+}
+
+// ElemDrop drops the element segment at elemIdx
+func (a *Assembler) ElemDrop(elemIdx uint32) *Assembler {
+	return a.misc(OpCodeMiscElemDrop, elemIdx)
+}
+
+// TableCopy copies from the table at src into the table at dst
+func (a *Assembler) TableCopy(dst, src uint32) *Assembler {
+	return a.misc(OpCodeMiscTableCopy, dst, src)
+}
+
+// TableGrow grows the table at tableIdx
+func (a *Assembler) TableGrow(tableIdx uint32) *Assembler {
+	return a.misc(OpCodeMiscTableGrow, tableIdx)
+}
+
+// TableSize appends table.size of the table at tableIdx
+func (a *Assembler) TableSize(tableIdx uint32) *Assembler {
+	return a.misc(OpCodeMiscTableSize, tableIdx)
+}
+
+// TableFill fills the table at tableIdx
+func (a *Assembler) TableFill(tableIdx uint32) *Assembler {
+	return a.misc(OpCodeMiscTableFill, tableIdx)
+}
+
+// Assemble returns the function body ended by the end of the function, which types.CodeSegmentBody is,
+// or the first error met. Every block must be closed.
+func (a *Assembler) Assemble() ([]byte, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	if n := len(a.blocks) - 1; n != 0 {
+		return nil, fmt.Errorf("assemble: %w: %d blocks not closed", common.ErrUnbalancedBlock, n)
+	}
+
+	ret := make([]byte, len(a.buf), len(a.buf)+1)
+	copy(ret, a.buf)
+	return append(ret, byte(OpCodeEnd)), nil
+}
@@ -0,0 +1,484 @@
+package operator
+
+// the loads and stores
+
+// I32Load appends i32.load of m
+func (a *Assembler) I32Load(m MemArg) *Assembler { return a.memArg(OpCodeI32Load, m) }
+
+// I64Load appends i64.load of m
+func (a *Assembler) I64Load(m MemArg) *Assembler { return a.memArg(OpCodeI64Load, m) }
+
+// F32Load appends f32.load of m
+func (a *Assembler) F32Load(m MemArg) *Assembler { return a.memArg(OpCodeF32Load, m) }
+
+// F64Load appends f64.load of m
+func (a *Assembler) F64Load(m MemArg) *Assembler { return a.memArg(OpCodeF64Load, m) }
+
+// I32Load8S appends i32.load8_s of m
+func (a *Assembler) I32Load8S(m MemArg) *Assembler { return a.memArg(OpCodeI32Load8s, m) }
+
+// I32Load8U appends i32.load8_u of m
+func (a *Assembler) I32Load8U(m MemArg) *Assembler { return a.memArg(OpCodeI32Load8u, m) }
+
+// I32Load16S appends i32.load16_s of m
+func (a *Assembler) I32Load16S(m MemArg) *Assembler { return a.memArg(OpCodeI32Load16s, m) }
+
+// I32Load16U appends i32.load16_u of m
+func (a *Assembler) I32Load16U(m MemArg) *Assembler { return a.memArg(OpCodeI32Load16u, m) }
+
+// I64Load8S appends i64.load8_s of m
+func (a *Assembler) I64Load8S(m MemArg) *Assembler { return a.memArg(OpCodeI64Load8s, m) }
+
+// I64Load8U appends i64.load8_u of m
+func (a *Assembler) I64Load8U(m MemArg) *Assembler { return a.memArg(OpCodeI64Load8u, m) }
+
+// I64Load16S appends i64.load16_s of m
+func (a *Assembler) I64Load16S(m MemArg) *Assembler { return a.memArg(OpCodeI64Load16s, m) }
+
+// I64Load16U appends i64.load16_u of m
+func (a *Assembler) I64Load16U(m MemArg) *Assembler { return a.memArg(OpCodeI64Load16u, m) }
+
+// I64Load32S appends i64.load32_s of m
+func (a *Assembler) I64Load32S(m MemArg) *Assembler { return a.memArg(OpCodeI64Load32s, m) }
+
+// I64Load32U appends i64.load32_u of m
+func (a *Assembler) I64Load32U(m MemArg) *Assembler { return a.memArg(OpCodeI64Load32u, m) }
+
+// I32Store appends i32.store of m
+func (a *Assembler) I32Store(m MemArg) *Assembler { return a.memArg(OpCodeI32Store, m) }
+
+// I64Store appends i64.store of m
+func (a *Assembler) I64Store(m MemArg) *Assembler { return a.memArg(OpCodeI64Store, m) }
+
+// F32Store appends f32.store of m
+func (a *Assembler) F32Store(m MemArg) *Assembler { return a.memArg(OpCodeF32Store, m) }
+
+// F64Store appends f64.store of m
+func (a *Assembler) F64Store(m MemArg) *Assembler { return a.memArg(OpCodeF64Store, m) }
+
+// I32Store8 appends i32.store8 of m
+func (a *Assembler) I32Store8(m MemArg) *Assembler { return a.memArg(OpCodeI32Store8, m) }
+
+// I32Store16 appends i32.store16 of m
+func (a *Assembler) I32Store16(m MemArg) *Assembler { return a.memArg(OpCodeI32Store16, m) }
+
+// I64Store8 appends i64.store8 of m
+func (a *Assembler) I64Store8(m MemArg) *Assembler { return a.memArg(OpCodeI64Store8, m) }
+
+// I64Store16 appends i64.store16 of m
+func (a *Assembler) I64Store16(m MemArg) *Assembler { return a.memArg(OpCodeI64Store16, m) }
+
+// I64Store32 appends i64.store32 of m
+func (a *Assembler) I64Store32(m MemArg) *Assembler { return a.memArg(OpCodeI64Store32, m) }
+
+// the numeric instructions without immediates
+
+// I32Eqz appends i32.eqz
+func (a *Assembler) I32Eqz() *Assembler { return a.op(OpCodeI32eqz) }
+
+// I32Eq appends i32.eq
+func (a *Assembler) I32Eq() *Assembler { return a.op(OpCodeI32eq) }
+
+// I32Ne appends i32.ne
+func (a *Assembler) I32Ne() *Assembler { return a.op(OpCodeI32ne) }
+
+// I32LtS appends i32.lt_s
+func (a *Assembler) I32LtS() *Assembler { return a.op(OpCodeI32lts) }
+
+// I32LtU appends i32.lt_u
+func (a *Assembler) I32LtU() *Assembler { return a.op(OpCodeI32ltu) }
+
+// I32GtS appends i32.gt_s
+func (a *Assembler) I32GtS() *Assembler { return a.op(OpCodeI32gts) }
+
+// I32GtU appends i32.gt_u
+func (a *Assembler) I32GtU() *Assembler { return a.op(OpCodeI32gtu) }
+
+// I32LeS appends i32.le_s
+func (a *Assembler) I32LeS() *Assembler { return a.op(OpCodeI32les) }
+
+// I32LeU appends i32.le_u
+func (a *Assembler) I32LeU() *Assembler { return a.op(OpCodeI32leu) }
+
+// I32GeS appends i32.ge_s
+func (a *Assembler) I32GeS() *Assembler { return a.op(OpCodeI32ges) }
+
+// I32GeU appends i32.ge_u
+func (a *Assembler) I32GeU() *Assembler { return a.op(OpCodeI32geu) }
+
+// I64Eqz appends i64.eqz
+func (a *Assembler) I64Eqz() *Assembler { return a.op(OpCodeI64eqz) }
+
+// I64Eq appends i64.eq
+func (a *Assembler) I64Eq() *Assembler { return a.op(OpCodeI64eq) }
+
+// I64Ne appends i64.ne
+func (a *Assembler) I64Ne() *Assembler { return a.op(OpCodeI64ne) }
+
+// I64LtS appends i64.lt_s
+func (a *Assembler) I64LtS() *Assembler { return a.op(OpCodeI64lts) }
+
+// I64LtU appends i64.lt_u
+func (a *Assembler) I64LtU() *Assembler { return a.op(OpCodeI64ltu) }
+
+// I64GtS appends i64.gt_s
+func (a *Assembler) I64GtS() *Assembler { return a.op(OpCodeI64gts) }
+
+// I64GtU appends i64.gt_u
+func (a *Assembler) I64GtU() *Assembler { return a.op(OpCodeI64gtu) }
+
+// I64LeS appends i64.le_s
+func (a *Assembler) I64LeS() *Assembler { return a.op(OpCodeI64les) }
+
+// I64LeU appends i64.le_u
+func (a *Assembler) I64LeU() *Assembler { return a.op(OpCodeI64leu) }
+
+// I64GeS appends i64.ge_s
+func (a *Assembler) I64GeS() *Assembler { return a.op(OpCodeI64ges) }
+
+// I64GeU appends i64.ge_u
+func (a *Assembler) I64GeU() *Assembler { return a.op(OpCodeI64geu) }
+
+// F32Eq appends f32.eq
+func (a *Assembler) F32Eq() *Assembler { return a.op(OpCodeF32eq) }
+
+// F32Ne appends f32.ne
+func (a *Assembler) F32Ne() *Assembler { return a.op(OpCodeF32ne) }
+
+// F32Lt appends f32.lt
+func (a *Assembler) F32Lt() *Assembler { return a.op(OpCodeF32lt) }
+
+// F32Gt appends f32.gt
+func (a *Assembler) F32Gt() *Assembler { return a.op(OpCodeF32gt) }
+
+// F32Le appends f32.le
+func (a *Assembler) F32Le() *Assembler { return a.op(OpCodeF32le) }
+
+// F32Ge appends f32.ge
+func (a *Assembler) F32Ge() *Assembler { return a.op(OpCodeF32ge) }
+
+// F64Eq appends f64.eq
+func (a *Assembler) F64Eq() *Assembler { return a.op(OpCodeF64eq) }
+
+// F64Ne appends f64.ne
+func (a *Assembler) F64Ne() *Assembler { return a.op(OpCodeF64ne) }
+
+// F64Lt appends f64.lt
+func (a *Assembler) F64Lt() *Assembler { return a.op(OpCodeF64lt) }
+
+// F64Gt appends f64.gt
+func (a *Assembler) F64Gt() *Assembler { return a.op(OpCodeF64gt) }
+
+// F64Le appends f64.le
+func (a *Assembler) F64Le() *Assembler { return a.op(OpCodeF64le) }
+
+// F64Ge appends f64.ge
+func (a *Assembler) F64Ge() *Assembler { return a.op(OpCodeF64ge) }
+
+// I32Clz appends i32.clz
+func (a *Assembler) I32Clz() *Assembler { return a.op(OpCodeI32clz) }
+
+// I32Ctz appends i32.ctz
+func (a *Assembler) I32Ctz() *Assembler { return a.op(OpCodeI32ctz) }
+
+// I32Popcnt appends i32.popcnt
+func (a *Assembler) I32Popcnt() *Assembler { return a.op(OpCodeI32popcnt) }
+
+// I32Add appends i32.add
+func (a *Assembler) I32Add() *Assembler { return a.op(OpCodeI32add) }
+
+// I32Sub appends i32.sub
+func (a *Assembler) I32Sub() *Assembler { return a.op(OpCodeI32sub) }
+
+// I32Mul appends i32.mul
+func (a *Assembler) I32Mul() *Assembler { return a.op(OpCodeI32mul) }
+
+// I32DivS appends i32.div_s
+func (a *Assembler) I32DivS() *Assembler { return a.op(OpCodeI32divs) }
+
+// I32DivU appends i32.div_u
+func (a *Assembler) I32DivU() *Assembler { return a.op(OpCodeI32divu) }
+
+// I32RemS appends i32.rem_s
+func (a *Assembler) I32RemS() *Assembler { return a.op(OpCodeI32rems) }
+
+// I32RemU appends i32.rem_u
+func (a *Assembler) I32RemU() *Assembler { return a.op(OpCodeI32remu) }
+
+// I32And appends i32.and
+func (a *Assembler) I32And() *Assembler { return a.op(OpCodeI32and) }
+
+// I32Or appends i32.or
+func (a *Assembler) I32Or() *Assembler { return a.op(OpCodeI32or) }
+
+// I32Xor appends i32.xor
+func (a *Assembler) I32Xor() *Assembler { return a.op(OpCodeI32xor) }
+
+// I32Shl appends i32.shl
+func (a *Assembler) I32Shl() *Assembler { return a.op(OpCodeI32shl) }
+
+// I32ShrS appends i32.shr_s
+func (a *Assembler) I32ShrS() *Assembler { return a.op(OpCodeI32shrs) }
+
+// I32ShrU appends i32.shr_u
+func (a *Assembler) I32ShrU() *Assembler { return a.op(OpCodeI32shru) }
+
+// I32Rotl appends i32.rotl
+func (a *Assembler) I32Rotl() *Assembler { return a.op(OpCodeI32rotl) }
+
+// I32Rotr appends i32.rotr
+func (a *Assembler) I32Rotr() *Assembler { return a.op(OpCodeI32rotr) }
+
+// I64Clz appends i64.clz
+func (a *Assembler) I64Clz() *Assembler { return a.op(OpCodeI64clz) }
+
+// I64Ctz appends i64.ctz
+func (a *Assembler) I64Ctz() *Assembler { return a.op(OpCodeI64ctz) }
+
+// I64Popcnt appends i64.popcnt
+func (a *Assembler) I64Popcnt() *Assembler { return a.op(OpCodeI64popcnt) }
+
+// I64Add appends i64.add
+func (a *Assembler) I64Add() *Assembler { return a.op(OpCodeI64add) }
+
+// I64Sub appends i64.sub
+func (a *Assembler) I64Sub() *Assembler { return a.op(OpCodeI64sub) }
+
+// I64Mul appends i64.mul
+func (a *Assembler) I64Mul() *Assembler { return a.op(OpCodeI64mul) }
+
+// I64DivS appends i64.div_s
+func (a *Assembler) I64DivS() *Assembler { return a.op(OpCodeI64divs) }
+
+// I64DivU appends i64.div_u
+func (a *Assembler) I64DivU() *Assembler { return a.op(OpCodeI64divu) }
+
+// I64RemS appends i64.rem_s
+func (a *Assembler) I64RemS() *Assembler { return a.op(OpCodeI64rems) }
+
+// I64RemU appends i64.rem_u
+func (a *Assembler) I64RemU() *Assembler { return a.op(OpCodeI64remu) }
+
+// I64And appends i64.and
+func (a *Assembler) I64And() *Assembler { return a.op(OpCodeI64and) }
+
+// I64Or appends i64.or
+func (a *Assembler) I64Or() *Assembler { return a.op(OpCodeI64or) }
+
+// I64Xor appends i64.xor
+func (a *Assembler) I64Xor() *Assembler { return a.op(OpCodeI64xor) }
+
+// I64Shl appends i64.shl
+func (a *Assembler) I64Shl() *Assembler { return a.op(OpCodeI64shl) }
+
+// I64ShrS appends i64.shr_s
+func (a *Assembler) I64ShrS() *Assembler { return a.op(OpCodeI64shrs) }
+
+// I64ShrU appends i64.shr_u
+func (a *Assembler) I64ShrU() *Assembler { return a.op(OpCodeI64shru) }
+
+// I64Rotl appends i64.rotl
+func (a *Assembler) I64Rotl() *Assembler { return a.op(OpCodeI64rotl) }
+
+// I64Rotr appends i64.rotr
+func (a *Assembler) I64Rotr() *Assembler { return a.op(OpCodeI64rotr) }
+
+// F32Abs appends f32.abs
+func (a *Assembler) F32Abs() *Assembler { return a.op(OpCodeF32abs) }
+
+// F32Neg appends f32.neg
+func (a *Assembler) F32Neg() *Assembler { return a.op(OpCodeF32neg) }
+
+// F32Ceil appends f32.ceil
+func (a *Assembler) F32Ceil() *Assembler { return a.op(OpCodeF32ceil) }
+
+// F32Floor appends f32.floor
+func (a *Assembler) F32Floor() *Assembler { return a.op(OpCodeF32floor) }
+
+// F32Trunc appends f32.trunc
+func (a *Assembler) F32Trunc() *Assembler { return a.op(OpCodeF32trunc) }
+
+// F32Nearest appends f32.nearest
+func (a *Assembler) F32Nearest() *Assembler { return a.op(OpCodeF32nearest) }
+
+// F32Sqrt appends f32.sqrt
+func (a *Assembler) F32Sqrt() *Assembler { return a.op(OpCodeF32sqrt) }
+
+// F32Add appends f32.add
+func (a *Assembler) F32Add() *Assembler { return a.op(OpCodeF32add) }
+
+// F32Sub appends f32.sub
+func (a *Assembler) F32Sub() *Assembler { return a.op(OpCodeF32sub) }
+
+// F32Mul appends f32.mul
+func (a *Assembler) F32Mul() *Assembler { return a.op(OpCodeF32mul) }
+
+// F32Div appends f32.div
+func (a *Assembler) F32Div() *Assembler { return a.op(OpCodeF32div) }
+
+// F32Min appends f32.min
+func (a *Assembler) F32Min() *Assembler { return a.op(OpCodeF32min) }
+
+// F32Max appends f32.max
+func (a *Assembler) F32Max() *Assembler { return a.op(OpCodeF32max) }
+
+// F32Copysign appends f32.copysign
+func (a *Assembler) F32Copysign() *Assembler { return a.op(OpCodeF32copysign) }
+
+// F64Abs appends f64.abs
+func (a *Assembler) F64Abs() *Assembler { return a.op(OpCodeF64abs) }
+
+// F64Neg appends f64.neg
+func (a *Assembler) F64Neg() *Assembler { return a.op(OpCodeF64neg) }
+
+// F64Ceil appends f64.ceil
+func (a *Assembler) F64Ceil() *Assembler { return a.op(OpCodeF64ceil) }
+
+// F64Floor appends f64.floor
+func (a *Assembler) F64Floor() *Assembler { return a.op(OpCodeF64floor) }
+
+// F64Trunc appends f64.trunc
+func (a *Assembler) F64Trunc() *Assembler { return a.op(OpCodeF64trunc) }
+
+// F64Nearest appends f64.nearest
+func (a *Assembler) F64Nearest() *Assembler { return a.op(OpCodeF64nearest) }
+
+// F64Sqrt appends f64.sqrt
+func (a *Assembler) F64Sqrt() *Assembler { return a.op(OpCodeF64sqrt) }
+
+// F64Add appends f64.add
+func (a *Assembler) F64Add() *Assembler { return a.op(OpCodeF64add) }
+
+// F64Sub appends f64.sub
+func (a *Assembler) F64Sub() *Assembler { return a.op(OpCodeF64sub) }
+
+// F64Mul appends f64.mul
+func (a *Assembler) F64Mul() *Assembler { return a.op(OpCodeF64mul) }
+
+// F64Div appends f64.div
+func (a *Assembler) F64Div() *Assembler { return a.op(OpCodeF64div) }
+
+// F64Min appends f64.min
+func (a *Assembler) F64Min() *Assembler { return a.op(OpCodeF64min) }
+
+// F64Max appends f64.max
+func (a *Assembler) F64Max() *Assembler { return a.op(OpCodeF64max) }
+
+// F64Copysign appends f64.copysign
+func (a *Assembler) F64Copysign() *Assembler { return a.op(OpCodeF64copysign) }
+
+// I32WrapI64 appends i32.wrap_i64
+func (a *Assembler) I32WrapI64() *Assembler { return a.op(OpCodeI32wrapI64) }
+
+// I32TruncF32S appends i32.trunc_f32_s
+func (a *Assembler) I32TruncF32S() *Assembler { return a.op(OpCodeI32truncf32s) }
+
+// I32TruncF32U appends i32.trunc_f32_u
+func (a *Assembler) I32TruncF32U() *Assembler { return a.op(OpCodeI32truncf32u) }
+
+// I32TruncF64S appends i32.trunc_f64_s
+func (a *Assembler) I32TruncF64S() *Assembler { return a.op(OpCodeI32truncf64s) }
+
+// I32TruncF64U appends i32.trunc_f64_u
+func (a *Assembler) I32TruncF64U() *Assembler { return a.op(OpCodeI32truncf64u) }
+
+// I64ExtendI32S appends i64.extend_i32_s
+func (a *Assembler) I64ExtendI32S() *Assembler { return a.op(OpCodeI64Extendi32s) }
+
+// I64ExtendI32U appends i64.extend_i32_u
+func (a *Assembler) I64ExtendI32U() *Assembler { return a.op(OpCodeI64Extendi32u) }
+
+// I64TruncF32S appends i64.trunc_f32_s
+func (a *Assembler) I64TruncF32S() *Assembler { return a.op(OpCodeI64TruncF32s) }
+
+// I64TruncF32U appends i64.trunc_f32_u
+func (a *Assembler) I64TruncF32U() *Assembler { return a.op(OpCodeI64TruncF32u) }
+
+// I64TruncF64S appends i64.trunc_f64_s
+func (a *Assembler) I64TruncF64S() *Assembler { return a.op(OpCodeI64Truncf64s) }
+
+// I64TruncF64U appends i64.trunc_f64_u
+func (a *Assembler) I64TruncF64U() *Assembler { return a.op(OpCodeI64Truncf64u) }
+
+// F32ConvertI32S appends f32.convert_i32_s
+func (a *Assembler) F32ConvertI32S() *Assembler { return a.op(OpCodeF32Converti32s) }
+
+// F32ConvertI32U appends f32.convert_i32_u
+func (a *Assembler) F32ConvertI32U() *Assembler { return a.op(OpCodeF32Converti32u) }
+
+// F32ConvertI64S appends f32.convert_i64_s
+func (a *Assembler) F32ConvertI64S() *Assembler { return a.op(OpCodeF32Converti64s) }
+
+// F32ConvertI64U appends f32.convert_i64_u
+func (a *Assembler) F32ConvertI64U() *Assembler { return a.op(OpCodeF32Converti64u) }
+
+// F32DemoteF64 appends f32.demote_f64
+func (a *Assembler) F32DemoteF64() *Assembler { return a.op(OpCodeF32Demotef64) }
+
+// F64ConvertI32S appends f64.convert_i32_s
+func (a *Assembler) F64ConvertI32S() *Assembler { return a.op(OpCodeF64Converti32s) }
+
+// F64ConvertI32U appends f64.convert_i32_u
+func (a *Assembler) F64ConvertI32U() *Assembler { return a.op(OpCodeF64Converti32u) }
+
+// F64ConvertI64S appends f64.convert_i64_s
+func (a *Assembler) F64ConvertI64S() *Assembler { return a.op(OpCodeF64Converti64s) }
+
+// F64ConvertI64U appends f64.convert_i64_u
+func (a *Assembler) F64ConvertI64U() *Assembler { return a.op(OpCodeF64Converti64u) }
+
+// F64PromoteF32 appends f64.promote_f32
+func (a *Assembler) F64PromoteF32() *Assembler { return a.op(OpCodeF64Promotef32) }
+
+// I32ReinterpretF32 appends i32.reinterpret_f32
+func (a *Assembler) I32ReinterpretF32() *Assembler { return a.op(OpCodeI32reinterpretf32) }
+
+// I64ReinterpretF64 appends i64.reinterpret_f64
+func (a *Assembler) I64ReinterpretF64() *Assembler { return a.op(OpCodeI64reinterpretf64) }
+
+// F32ReinterpretI32 appends f32.reinterpret_i32
+func (a *Assembler) F32ReinterpretI32() *Assembler { return a.op(OpCodeF32reinterpreti32) }
+
+// F64ReinterpretI64 appends f64.reinterpret_i64
+func (a *Assembler) F64ReinterpretI64() *Assembler { return a.op(OpCodeF64reinterpreti64) }
+
+// I32Extend8S appends i32.extend8_s
+func (a *Assembler) I32Extend8S() *Assembler { return a.op(OpCodeI32Extend8s) }
+
+// I32Extend16S appends i32.extend16_s
+func (a *Assembler) I32Extend16S() *Assembler { return a.op(OpCodeI32Extend16s) }
+
+// I64Extend8S appends i64.extend8_s
+func (a *Assembler) I64Extend8S() *Assembler { return a.op(OpCodeI64Extend8s) }
+
+// I64Extend16S appends i64.extend16_s
+func (a *Assembler) I64Extend16S() *Assembler { return a.op(OpCodeI64Extend16s) }
+
+// I64Extend32S appends i64.extend32_s
+func (a *Assembler) I64Extend32S() *Assembler { return a.op(OpCodeI64Extend32s) }
+
+// the non-trapping float-to-int conversions
+
+// I32TruncSatF32S appends i32.trunc_sat_f32_s
+func (a *Assembler) I32TruncSatF32S() *Assembler { return a.misc(OpCodeMiscI32TruncSatF32s) }
+
+// I32TruncSatF32U appends i32.trunc_sat_f32_u
+func (a *Assembler) I32TruncSatF32U() *Assembler { return a.misc(OpCodeMiscI32TruncSatF32u) }
+
+// I32TruncSatF64S appends i32.trunc_sat_f64_s
+func (a *Assembler) I32TruncSatF64S() *Assembler { return a.misc(OpCodeMiscI32TruncSatF64s) }
+
+// I32TruncSatF64U appends i32.trunc_sat_f64_u
+func (a *Assembler) I32TruncSatF64U() *Assembler { return a.misc(OpCodeMiscI32TruncSatF64u) }
+
+// I64TruncSatF32S appends i64.trunc_sat_f32_s
+func (a *Assembler) I64TruncSatF32S() *Assembler { return a.misc(OpCodeMiscI64TruncSatF32s) }
+
+// I64TruncSatF32U appends i64.trunc_sat_f32_u
+func (a *Assembler) I64TruncSatF32U() *Assembler { return a.misc(OpCodeMiscI64TruncSatF32u) }
+
+// I64TruncSatF64S appends i64.trunc_sat_f64_s
+func (a *Assembler) I64TruncSatF64S() *Assembler { return a.misc(OpCodeMiscI64TruncSatF64s) }
+
+// I64TruncSatF64U appends i64.trunc_sat_f64_u
+func (a *Assembler) I64TruncSatF64U() *Assembler { return a.misc(OpCodeMiscI64TruncSatF64u) }
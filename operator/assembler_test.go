@@ -0,0 +1,65 @@
+package operator
+
+import (
+	"errors"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAssembler(t *testing.T) {
+	a := NewAssembler()
+	outer := a.Block(BlockVoid)
+	loop := a.Loop(BlockVoid)
+	a.LocalGet(0).I32Eqz().BrIf(outer)
+	a.LocalGet(0).I32Const(-1).I32Add().LocalSet(0)
+	a.LocalGet(1).I32Load(MemArg{Align: 2, Offset: 128}).Drop()
+	a.LocalGet(0).If(BlockResult(0x7f))
+	a.I32Const(1).Else().I32Const(0).End()
+	a.BrTable([]Label{loop, outer}, BodyLabel)
+	a.End().End()
+
+	body, err := a.Assemble()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{
+		0x02, 0x40, // block
+		0x03, 0x40, // loop
+		0x20, 0x00, 0x45, 0x0d, 0x01, // br_if outer
+		0x20, 0x00, 0x41, 0x7f, 0x6a, 0x21, 0x00,
+		0x20, 0x01, 0x28, 0x02, 0x80, 0x01, 0x1a,
+		0x20, 0x00, 0x04, 0x7f, 0x41, 0x01, 0x05, 0x41, 0x00, 0x0b,
+		0x0e, 0x02, 0x00, 0x01, 0x02, // br_table
+		0x0b, 0x0b, 0x0b,
+	}, body)
+
+	// every instruction is decoded back
+	for off := 0; off < len(body); {
+		_, n, err := ReadInstruction(body[off:])
+		assert.Nil(t, err)
+		off += n
+	}
+}
+
+func TestAssemblerErrors(t *testing.T) {
+	a := NewAssembler()
+	a.Block(BlockFunc(3))
+	_, err := a.Assemble()
+	assert.True(t, errors.Is(err, common.ErrUnbalancedBlock))
+
+	a = NewAssembler()
+	a.End()
+	_, err = a.Assemble()
+	assert.True(t, errors.Is(err, common.ErrUnbalancedBlock))
+
+	a = NewAssembler()
+	a.Block(BlockVoid)
+	a.Else()
+	_, err = a.Assemble()
+	assert.True(t, errors.Is(err, common.ErrUnbalancedBlock))
+
+	a = NewAssembler()
+	l := a.Block(BlockVoid)
+	a.End().Br(l)
+	_, err = a.Assemble()
+	assert.True(t, errors.Is(err, common.ErrUnknownLabel))
+}
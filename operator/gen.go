@@ -0,0 +1,187 @@
+//go:build ignore
+// +build ignore
+
+// gen generates opcode_table.go from opcodes.txt
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var imms = map[string]string{
+	"blocktype": "ImmBlockType",
+	"label":     "ImmLabel",
+	"labels":    "ImmLabels",
+	"func":      "ImmFunc",
+	"type":      "ImmType",
+	"table":     "ImmTable",
+	"memory":    "ImmMemory",
+	"local":     "ImmLocal",
+	"global":    "ImmGlobal",
+	"elem":      "ImmElem",
+	"data":      "ImmData",
+	"tag":       "ImmTag",
+	"memarg":    "ImmMemArg",
+	"i32":       "ImmI32",
+	"i64":       "ImmI64",
+	"f32":       "ImmF32",
+	"f64":       "ImmF64",
+	"v128":      "ImmV128",
+	"lane":      "ImmLane",
+	"lanes16":   "ImmLanes16",
+	"reftype":   "ImmRefType",
+	"valtypes":  "ImmValueTypes",
+	"byte":      "ImmByte",
+}
+
+var valueTypes = map[string]string{
+	"i32":       "typeI32",
+	"i64":       "typeI64",
+	"f32":       "typeF32",
+	"f64":       "typeF64",
+	"v128":      "typeV128",
+	"funcref":   "typeFuncRef",
+	"externref": "typeExternRef",
+}
+
+var features = map[string]string{
+	"sign-extension":      "feature.SignExtension",
+	"nontrapping-fptoint": "feature.NonTrappingFloatToInt",
+	"bulk-memory":         "feature.BulkMemory",
+	"reference-types":     "feature.ReferenceTypes",
+	"simd":                "feature.SIMD",
+	"threads":             "feature.Threads",
+	"tail-call":           "feature.TailCall",
+	"exception-handling":  "feature.ExceptionHandling",
+}
+
+var prefixes = map[string]string{
+	"0xfc": "OpCodePrefixMisc",
+	"0xfd": "OpCodePrefixSIMD",
+	"0xfe": "OpCodePrefixAtomic",
+}
+
+func main() {
+	f, err := os.Open("opcodes.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen.go from opcodes.txt; DO NOT EDIT.\n\n")
+	buf.WriteString("package operator\n\nimport \"github.com/LBruyne/wasm-decode/feature\"\n\n")
+	buf.WriteString("var infos = []Info{\n")
+
+	s := bufio.NewScanner(f)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		entry, err := parse(strings.Fields(text))
+		if err != nil {
+			log.Fatalf("opcodes.txt:%d: %v", line, err)
+		}
+		buf.WriteString("\t" + entry + ",\n")
+	}
+	if err := s.Err(); err != nil {
+		log.Fatal(err)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("opcode_table.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parse returns the Info literal of the columns of a line
+func parse(cols []string) (string, error) {
+	if len(cols) != 7 {
+		return "", fmt.Errorf("%d columns", len(cols))
+	}
+
+	var fields []string
+	code := strings.SplitN(cols[0], ".", 2)
+	if len(code) == 2 {
+		prefix, ok := prefixes[code[0]]
+		if !ok {
+			return "", fmt.Errorf("unknown prefix: %s", code[0])
+		}
+		if _, err := strconv.ParseUint(code[1], 0, 32); err != nil {
+			return "", err
+		}
+		fields = append(fields, "OpCode: "+prefix, "Sub: "+code[1])
+	} else {
+		if _, err := strconv.ParseUint(code[0], 0, 8); err != nil {
+			return "", err
+		}
+		fields = append(fields, "OpCode: "+code[0])
+	}
+	fields = append(fields, "Name: "+strconv.Quote(cols[1]))
+
+	if cols[2] != "-" {
+		list, err := idents(imms, cols[2])
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, "Imms: []Immediate{"+list+"}")
+	}
+
+	if cols[3] == "*" || cols[4] == "*" {
+		fields = append(fields, "Dynamic: true")
+	} else {
+		for i, name := range []string{"Params", "Results"} {
+			if cols[3+i] == "-" {
+				continue
+			}
+			list, err := idents(valueTypes, cols[3+i])
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, name+": []byte{"+list+"}")
+		}
+	}
+
+	if cols[5] != "-" {
+		f, ok := features[cols[5]]
+		if !ok {
+			return "", fmt.Errorf("unknown feature: %s", cols[5])
+		}
+		fields = append(fields, "Feature: "+f)
+	}
+
+	switch cols[6] {
+	case "const":
+		fields = append(fields, "Const: true")
+	case "-":
+	default:
+		return "", fmt.Errorf("unknown flag: %s", cols[6])
+	}
+	return "{" + strings.Join(fields, ", ") + "}", nil
+}
+
+// idents returns the identifiers of the comma separated names
+func idents(m map[string]string, names string) (string, error) {
+	var ret []string
+	for _, n := range strings.Split(names, ",") {
+		id, ok := m[n]
+		if !ok {
+			return "", fmt.Errorf("unknown name: %s", n)
+		}
+		ret = append(ret, id)
+	}
+	return strings.Join(ret, ", "), nil
+}
@@ -0,0 +1,183 @@
+package operator
+
+import (
+	"fmt"
+	"github.com/LBruyne/wasm-decode/common"
+	"io"
+)
+
+// Instruction is an instruction decoded from a code body
+type Instruction struct {
+	OpCode OpCode
+	Sub    uint32 // sub OpCode when OpCode is a prefix
+	Imm    []byte // raw bytes of the immediates
+}
+
+// ReadInstruction decodes the instruction at the beginning of b,
+// it returns the instruction and the number of bytes it takes
+func ReadInstruction(b []byte) (*Instruction, int, error) {
+	ins := &Instruction{}
+	n, err := DecodeInstruction(b, ins)
+	if err != nil {
+		return nil, 0, err
+	}
+	return ins, n, nil
+}
+
+// DecodeInstruction decodes the instruction at the beginning of b into ins, whose Imm shares the memory of b.
+// It returns the number of bytes the instruction takes, and allocates nothing to go over a body.
+func DecodeInstruction(b []byte, ins *Instruction) (int, error) {
+	if len(b) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	*ins = Instruction{OpCode: OpCode(b[0])}
+	start := 1
+	switch ins.OpCode {
+	case OpCodePrefixMisc, OpCodePrefixSIMD, OpCodePrefixAtomic:
+		sub, n, err := common.DecodeUint32Bytes(b[1:])
+		if err != nil {
+			return 0, fmt.Errorf("read sub OpCode of %#x: %w", b[0], err)
+		}
+		ins.Sub = sub
+		start += n
+	}
+
+	n, err := skipImmediates(b[start:], ins)
+	if err != nil {
+		return 0, fmt.Errorf("read immediates of %#x: %w", b[0], err)
+	}
+
+	end := start + n
+	ins.Imm = b[start:end:end]
+	return end, nil
+}
+
+// skipImmediates reads over the immediates of ins at the beginning of b, as described by its Info,
+// it returns the number of bytes they take
+func skipImmediates(b []byte, ins *Instruction) (int, error) {
+	info, ok := ins.Info()
+	if !ok {
+		if _, prefix := prefixed[ins.OpCode]; prefix {
+			return 0, fmt.Errorf("unknown sub OpCode: %#x %#x", byte(ins.OpCode), ins.Sub)
+		}
+		return 0, fmt.Errorf("unknown OpCode: %#x", byte(ins.OpCode))
+	}
+
+	off := 0
+	for _, imm := range info.Imms {
+		n, err := skipImmediate(b[off:], imm)
+		if err != nil {
+			return 0, err
+		}
+		off += n
+	}
+	return off, nil
+}
+
+func skipImmediate(b []byte, imm Immediate) (int, error) {
+	switch imm {
+	case ImmBlockType:
+		return skipBlockType(b)
+	case ImmLabels:
+		n, size, err := common.DecodeUint32Bytes(b)
+		if err != nil {
+			return 0, err
+		}
+		m, err := skipUint32s(b[size:], int(n)+1)
+		return size + m, err
+	case ImmValueTypes:
+		n, size, err := common.DecodeUint32Bytes(b)
+		if err != nil {
+			return 0, err
+		}
+		m, err := skipBytes(b[size:], int(n))
+		return size + m, err
+	case ImmMemArg:
+		return skipMemArg(b)
+	case ImmI32:
+		_, n, err := common.DecodeInt32Bytes(b)
+		return n, err
+	case ImmI64:
+		_, n, err := common.DecodeInt64Bytes(b)
+		return n, err
+	case ImmF32:
+		return skipBytes(b, 4)
+	case ImmF64:
+		return skipBytes(b, 8)
+	case ImmV128, ImmLanes16:
+		return skipBytes(b, 16)
+	case ImmLane, ImmRefType, ImmByte:
+		return skipBytes(b, 1)
+	default:
+		return skipUint32s(b, 1)
+	}
+}
+
+// skipBlockType reads over a block type, which is 0x40, a value type or a type index in s33
+func skipBlockType(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if b[0] == BlockTypeEmpty || IsValueTypeByte(b[0]) {
+		return 1, nil
+	}
+
+	_, n, err := common.DecodeInt33Bytes(b)
+	return n, err
+}
+
+// skipMemArg reads over a memory argument, whose alignment has bit 6 set if a memory index follows
+func skipMemArg(b []byte) (int, error) {
+	align, off, err := common.DecodeUint32Bytes(b)
+	if err != nil {
+		return 0, err
+	}
+	if align&MemArgMemIdxFlag != 0 {
+		n, err := skipUint32s(b[off:], 1)
+		if err != nil {
+			return 0, err
+		}
+		off += n
+	}
+	_, n, err := common.DecodeUint64Bytes(b[off:])
+	return off + n, err
+}
+
+func skipUint32s(b []byte, n int) (int, error) {
+	off := 0
+	for i := 0; i < n; i++ {
+		_, size, err := common.DecodeUint32Bytes(b[off:])
+		if err != nil {
+			return 0, err
+		}
+		off += size
+	}
+	return off, nil
+}
+
+func skipBytes(b []byte, n int) (int, error) {
+	if len(b) < n {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return n, nil
+}
+
+// IsValueTypeByte reports whether b encodes a value type
+func IsValueTypeByte(b byte) bool {
+	return (b >= 0x7b && b <= 0x7f) || b == 0x70 || b == 0x6f
+}
+
+// BlockTypeIndex returns the type index if imm, the immediates of block, loop, if or try,
+// refers to a function type instead of 0x40 or a value type
+func BlockTypeIndex(imm []byte) (uint32, bool) {
+	if len(imm) == 0 || imm[0] == BlockTypeEmpty || IsValueTypeByte(imm[0]) {
+		return 0, false
+	}
+
+	idx, _, err := common.DecodeInt33Bytes(imm)
+	if err != nil || idx < 0 {
+		return 0, false
+	}
+	return uint32(idx), true
+}
@@ -0,0 +1,333 @@
+package operator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/feature"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+//go:generate go run gen.go
+
+// Immediate is the kind of an immediate of an instruction
+type Immediate byte
+
+const (
+	ImmBlockType  Immediate = iota + 1 // block type, 0x40, a value type or a type index in s33
+	ImmLabel                           // relative depth of a label
+	ImmLabels                          // vector of relative depths followed by the default one
+	ImmFunc                            // function index
+	ImmType                            // type index
+	ImmTable                           // table index
+	ImmMemory                          // memory index, a zero byte in WASM 1.0
+	ImmLocal                           // local index
+	ImmGlobal                          // global index
+	ImmElem                            // element index
+	ImmData                            // data index
+	ImmTag                             // tag index, defined by exception handling proposal
+	ImmMemArg                          // memory argument, see MemArg
+	ImmI32                             // i32 in signed LEB128
+	ImmI64                             // i64 in signed LEB128
+	ImmF32                             // f32 in 4 bytes
+	ImmF64                             // f64 in 8 bytes
+	ImmV128                            // v128 in 16 bytes
+	ImmLane                            // lane index in a byte
+	ImmLanes16                         // 16 lane indices in bytes
+	ImmRefType                         // reference type in a byte
+	ImmValueTypes                      // vector of value types
+	ImmByte                            // reserved byte
+)
+
+var immNames = map[Immediate]string{
+	ImmBlockType:  "blocktype",
+	ImmLabel:      "label",
+	ImmLabels:     "labels",
+	ImmFunc:       "func",
+	ImmType:       "type",
+	ImmTable:      "table",
+	ImmMemory:     "memory",
+	ImmLocal:      "local",
+	ImmGlobal:     "global",
+	ImmElem:       "elem",
+	ImmData:       "data",
+	ImmTag:        "tag",
+	ImmMemArg:     "memarg",
+	ImmI32:        "i32",
+	ImmI64:        "i64",
+	ImmF32:        "f32",
+	ImmF64:        "f64",
+	ImmV128:       "v128",
+	ImmLane:       "lane",
+	ImmLanes16:    "lanes16",
+	ImmRefType:    "reftype",
+	ImmValueTypes: "valtypes",
+	ImmByte:       "byte",
+}
+
+func (imm Immediate) String() string {
+	if n, ok := immNames[imm]; ok {
+		return n
+	}
+	return fmt.Sprintf("immediate(%d)", byte(imm))
+}
+
+// codes of the value types in the stack effects
+const (
+	typeI32       byte = 0x7f
+	typeI64       byte = 0x7e
+	typeF32       byte = 0x7d
+	typeF64       byte = 0x7c
+	typeV128      byte = 0x7b
+	typeFuncRef   byte = 0x70
+	typeExternRef byte = 0x6f
+)
+
+// Info describes an instruction. The memory instructions are described for 32-bit memories,
+// whose addresses are i32.
+type Info struct {
+	OpCode OpCode
+	Sub    uint32 // sub OpCode when OpCode is a prefix
+	Name   string // mnemonic of the text format, e.g. "i32.add"
+	Imms   []Immediate
+
+	Params  []byte // codes of the value types popped, the first one is the deepest
+	Results []byte // codes of the value types pushed
+	Dynamic bool   // the stack effect depends on the immediates or the context, Params and Results are unknown
+
+	Feature feature.Feature // proposal defining the instruction, 0 for WASM 1.0
+	Const   bool            // allowed in constant expressions
+}
+
+var (
+	oneByte  [256]*Info
+	prefixed = map[OpCode]map[uint32]*Info{}
+)
+
+func init() {
+	for i := range infos {
+		info := &infos[i]
+		switch info.OpCode {
+		case OpCodePrefixMisc, OpCodePrefixSIMD, OpCodePrefixAtomic:
+			if prefixed[info.OpCode] == nil {
+				prefixed[info.OpCode] = map[uint32]*Info{}
+			}
+			prefixed[info.OpCode][info.Sub] = info
+		default:
+			oneByte[info.OpCode] = info
+		}
+	}
+}
+
+// Lookup returns the description of the instruction of op, and sub if op is a prefix
+func Lookup(op OpCode, sub uint32) (*Info, bool) {
+	switch op {
+	case OpCodePrefixMisc, OpCodePrefixSIMD, OpCodePrefixAtomic:
+		info, ok := prefixed[op][sub]
+		return info, ok
+	}
+	info := oneByte[op]
+	return info, info != nil
+}
+
+// Infos returns the descriptions of all the known instructions, ordered by OpCode and sub OpCode
+func Infos() []*Info {
+	ret := make([]*Info, len(infos))
+	for i := range infos {
+		ret[i] = &infos[i]
+	}
+	return ret
+}
+
+// String returns the mnemonic of op, or its value if it is a prefix or unknown
+func (op OpCode) String() string {
+	if info := oneByte[op]; info != nil {
+		return info.Name
+	}
+	return fmt.Sprintf("opcode(%#x)", byte(op))
+}
+
+// Info returns the description of ins
+func (ins *Instruction) Info() (*Info, bool) {
+	return Lookup(ins.OpCode, ins.Sub)
+}
+
+// Name returns the mnemonic of ins, or its OpCodes if it is unknown
+func (ins *Instruction) Name() string {
+	if info, ok := ins.Info(); ok {
+		return info.Name
+	}
+	if _, ok := prefixed[ins.OpCode]; ok {
+		return fmt.Sprintf("opcode(%#x %#x)", byte(ins.OpCode), ins.Sub)
+	}
+	return ins.OpCode.String()
+}
+
+var valueTypeNames = map[byte]string{
+	typeI32:       "i32",
+	typeI64:       "i64",
+	typeF32:       "f32",
+	typeF64:       "f64",
+	typeV128:      "v128",
+	typeFuncRef:   "funcref",
+	typeExternRef: "externref",
+}
+
+func valueTypeName(code byte) string {
+	if n, ok := valueTypeNames[code]; ok {
+		return n
+	}
+	return fmt.Sprintf("type(%#x)", code)
+}
+
+// String returns ins in the text format, e.g. "i32.load offset=8 align=4", or its OpCodes if it is unknown
+func (ins *Instruction) String() string {
+	info, ok := ins.Info()
+	if !ok {
+		return ins.Name()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(info.Name)
+	r := bytes.NewReader(ins.Imm)
+	for _, imm := range info.Imms {
+		text, err := immediateText(r, imm)
+		if err != nil {
+			fmt.Fprintf(&sb, " invalid(%v)", err)
+			break
+		}
+		if text != "" {
+			sb.WriteString(" " + text)
+		}
+	}
+	return sb.String()
+}
+
+// immediateText reads an immediate of kind imm and returns it in the text format
+func immediateText(r *bytes.Reader, imm Immediate) (string, error) {
+	switch imm {
+	case ImmBlockType:
+		b, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if b == BlockTypeEmpty {
+			return "", nil
+		}
+		if IsValueTypeByte(b) {
+			return "(result " + valueTypeName(b) + ")", nil
+		}
+		if err := r.UnreadByte(); err != nil {
+			return "", err
+		}
+		idx, _, err := common.DecodeInt33(r)
+		return fmt.Sprintf("(type %d)", idx), err
+	case ImmLabels:
+		n, _, err := common.DecodeUint32(r)
+		if err != nil {
+			return "", err
+		}
+		if int64(n) >= int64(r.Len()) {
+			return "", io.ErrUnexpectedEOF
+		}
+		depths := make([]string, n+1)
+		for i := range depths {
+			d, _, err := common.DecodeUint32(r)
+			if err != nil {
+				return "", err
+			}
+			depths[i] = strconv.FormatUint(uint64(d), 10)
+		}
+		return strings.Join(depths, " "), nil
+	case ImmMemArg:
+		align, _, err := common.DecodeUint32(r)
+		if err != nil {
+			return "", err
+		}
+		var mem string
+		if align&MemArgMemIdxFlag != 0 {
+			idx, _, err := common.DecodeUint32(r)
+			if err != nil {
+				return "", err
+			}
+			align &^= MemArgMemIdxFlag
+			mem = fmt.Sprintf("%d ", idx)
+		}
+		off, _, err := common.DecodeUint64(r)
+		if err != nil {
+			return "", err
+		}
+		if off == 0 {
+			return fmt.Sprintf("%salign=%d", mem, uint64(1)<<align), nil
+		}
+		return fmt.Sprintf("%soffset=%d align=%d", mem, off, uint64(1)<<align), nil
+	case ImmI32:
+		v, _, err := common.DecodeInt32(r)
+		return strconv.FormatInt(int64(v), 10), err
+	case ImmI64:
+		v, _, err := common.DecodeInt64(r)
+		return strconv.FormatInt(v, 10), err
+	case ImmF32:
+		var b [4]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b[:]))), 'g', -1, 32), nil
+	case ImmF64:
+		var b [8]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(b[:])), 'g', -1, 64), nil
+	case ImmV128:
+		var b [16]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("i32x4 %#x %#x %#x %#x", binary.LittleEndian.Uint32(b[0:]), binary.LittleEndian.Uint32(b[4:]),
+			binary.LittleEndian.Uint32(b[8:]), binary.LittleEndian.Uint32(b[12:])), nil
+	case ImmLane:
+		b, err := r.ReadByte()
+		return strconv.Itoa(int(b)), err
+	case ImmLanes16:
+		var b [16]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return "", err
+		}
+		lanes := make([]string, len(b))
+		for i, l := range b {
+			lanes[i] = strconv.Itoa(int(l))
+		}
+		return strings.Join(lanes, " "), nil
+	case ImmRefType:
+		b, err := r.ReadByte()
+		return strings.TrimSuffix(valueTypeName(b), "ref"), err
+	case ImmValueTypes:
+		n, _, err := common.DecodeUint32(r)
+		if err != nil {
+			return "", err
+		}
+		if int64(n) > int64(r.Len()) {
+			return "", io.ErrUnexpectedEOF
+		}
+		codes := make([]byte, n)
+		if _, err := io.ReadFull(r, codes); err != nil {
+			return "", err
+		}
+		names := make([]string, n)
+		for i, c := range codes {
+			names[i] = valueTypeName(c)
+		}
+		return "(result " + strings.Join(names, " ") + ")", nil
+	case ImmByte:
+		_, err := r.ReadByte()
+		return "", err
+	default:
+		v, _, err := common.DecodeUint32(r)
+		return strconv.FormatUint(uint64(v), 10), err
+	}
+}
@@ -0,0 +1,114 @@
+package operator
+
+import (
+	"github.com/LBruyne/wasm-decode/feature"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	info, ok := Lookup(OpCodeI32add, 0)
+	if assert.True(t, ok) {
+		assert.Equal(t, "i32.add", info.Name)
+		assert.Equal(t, []byte{typeI32, typeI32}, info.Params)
+		assert.Equal(t, []byte{typeI32}, info.Results)
+		assert.Equal(t, feature.Feature(0), info.Feature)
+		assert.False(t, info.Const)
+	}
+
+	info, ok = Lookup(OpCodePrefixMisc, OpCodeMiscMemoryInit)
+	if assert.True(t, ok) {
+		assert.Equal(t, "memory.init", info.Name)
+		assert.Equal(t, []Immediate{ImmData, ImmMemory}, info.Imms)
+		assert.Equal(t, feature.BulkMemory, info.Feature)
+	}
+
+	info, ok = Lookup(OpCodePrefixSIMD, 0x54)
+	if assert.True(t, ok) {
+		assert.Equal(t, "v128.load8_lane", info.Name)
+		assert.Equal(t, []Immediate{ImmMemArg, ImmLane}, info.Imms)
+	}
+
+	info, ok = Lookup(OpCodeCall, 0)
+	if assert.True(t, ok) {
+		assert.True(t, info.Dynamic)
+		assert.Nil(t, info.Params)
+	}
+
+	_, ok = Lookup(0x0a, 0)
+	assert.False(t, ok)
+	_, ok = Lookup(OpCodePrefixSIMD, 0x9a)
+	assert.False(t, ok)
+	_, ok = Lookup(OpCodePrefixAtomic, 0x4f)
+	assert.False(t, ok)
+}
+
+func TestInfos(t *testing.T) {
+	var consts []string
+	for _, info := range Infos() {
+		if info.Const {
+			consts = append(consts, info.Name)
+		}
+		if !info.Dynamic {
+			for _, vt := range append(info.Params, info.Results...) {
+				assert.True(t, IsValueTypeByte(vt), info.Name)
+			}
+		}
+	}
+	assert.Equal(t, []string{"global.get", "i32.const", "i64.const", "f32.const", "f64.const",
+		"ref.null", "ref.func", "v128.const"}, consts)
+
+	assert.Equal(t, "local.tee", OpCodeLocalTee.String())
+	assert.Equal(t, "opcode(0xfc)", OpCodePrefixMisc.String())
+	assert.Equal(t, "memarg", ImmMemArg.String())
+}
+
+func TestInstructionName(t *testing.T) {
+	ins, _, err := ReadInstruction([]byte{byte(OpCodePrefixAtomic), 0x48, 0x02, 0x00})
+	assert.Nil(t, err)
+	assert.Equal(t, "i32.atomic.rmw.cmpxchg", ins.Name())
+
+	_, _, err = ReadInstruction([]byte{byte(OpCodePrefixSIMD), 0x9a, 0x01})
+	assert.NotNil(t, err)
+	assert.Equal(t, "opcode(0xfd 0x9a)", (&Instruction{OpCode: OpCodePrefixSIMD, Sub: 0x9a}).Name())
+}
+
+func TestInstructionString(t *testing.T) {
+	body, err := NewAssembler().
+		I32Load(MemArg{Align: 2, Offset: 8}).
+		I64Store(MemArg{Align: 3, MemIdx: 1}).
+		I32Const(-7).
+		F64Const(1.5).
+		SelectTyped(0x7f).
+		RefNull(0x70).
+		BrTable([]Label{BodyLabel}, BodyLabel).
+		Assemble()
+	assert.Nil(t, err)
+
+	var texts []string
+	for off := 0; off < len(body); {
+		ins, n, err := ReadInstruction(body[off:])
+		assert.Nil(t, err)
+		texts = append(texts, ins.String())
+		off += n
+	}
+	assert.Equal(t, []string{
+		"i32.load offset=8 align=4",
+		"i64.store 1 align=8",
+		"i32.const -7",
+		"f64.const 1.5",
+		"select (result i32)",
+		"ref.null func",
+		"br_table 0 0",
+		"end",
+	}, texts)
+
+	a := NewAssembler()
+	a.Block(BlockResult(0x7e))
+	a.End()
+	body, err = a.Assemble()
+	assert.Nil(t, err)
+	ins, _, err := ReadInstruction(body)
+	assert.Nil(t, err)
+	assert.Equal(t, "block (result i64)", ins.String())
+}
@@ -10,6 +10,10 @@ const (
 	OpCodeLoop         OpCode = 0x03
 	OpCodeIf           OpCode = 0x04
 	OpCodeElse         OpCode = 0x05
+	OpCodeTry          OpCode = 0x06 // exception handling proposal
+	OpCodeCatch        OpCode = 0x07 // exception handling proposal
+	OpCodeThrow        OpCode = 0x08 // exception handling proposal
+	OpCodeRethrow      OpCode = 0x09 // exception handling proposal
 	OpCodeEnd          OpCode = 0x0b
 	OpCodeBr           OpCode = 0x0c
 	OpCodeBrIf         OpCode = 0x0d
@@ -18,10 +22,17 @@ const (
 	OpCodeCall         OpCode = 0x10
 	OpCodeCallIndirect OpCode = 0x11
 
+	OpCodeReturnCall         OpCode = 0x12 // tail call proposal
+	OpCodeReturnCallIndirect OpCode = 0x13 // tail call proposal
+	OpCodeDelegate           OpCode = 0x18 // exception handling proposal
+	OpCodeCatchAll           OpCode = 0x19 // exception handling proposal
+
 	// parametric instruction
 	OpCodeDrop   OpCode = 0x1a
 	OpCodeSelect OpCode = 0x1b
 
+	OpCodeSelectTyped OpCode = 0x1c // reference types proposal
+
 	// variable instruction
 	OpCodeLocalGet  OpCode = 0x20
 	OpCodeLocalSet  OpCode = 0x21
@@ -29,6 +40,10 @@ const (
 	OpCodeGlobalGet OpCode = 0x23
 	OpCodeGlobalSet OpCode = 0x24
 
+	// table instruction, reference types proposal
+	OpCodeTableGet OpCode = 0x25
+	OpCodeTableSet OpCode = 0x26
+
 	// memory instruction
 	OpCodeI32Load    OpCode = 0x28
 	OpCodeI64Load    OpCode = 0x29
@@ -197,4 +212,69 @@ const (
 	OpCodeI64reinterpretf64 OpCode = 0xbd
 	OpCodeF32reinterpreti32 OpCode = 0xbe
 	OpCodeF64reinterpreti64 OpCode = 0xbf
+
+	// sign extension proposal
+	OpCodeI32Extend8s  OpCode = 0xc0
+	OpCodeI32Extend16s OpCode = 0xc1
+	OpCodeI64Extend8s  OpCode = 0xc2
+	OpCodeI64Extend16s OpCode = 0xc3
+	OpCodeI64Extend32s OpCode = 0xc4
+
+	// reference instruction
+	OpCodeRefNull   OpCode = 0xd0
+	OpCodeRefIsNull OpCode = 0xd1
+	OpCodeRefFunc   OpCode = 0xd2
+
+	// prefix of multi-byte instruction
+	OpCodePrefixMisc   OpCode = 0xfc
+	OpCodePrefixSIMD   OpCode = 0xfd
+	OpCodePrefixAtomic OpCode = 0xfe
+)
+
+const (
+	// BlockTypeEmpty is the block type of blocks without result
+	BlockTypeEmpty byte = 0x40
+
+	// MemArgMemIdxFlag is set in the alignment of a memory argument followed by a memory index,
+	// defined by multi-memory proposal
+	MemArgMemIdxFlag uint32 = 0x40
+)
+
+// sub OpCode following OpCodePrefixMisc
+const (
+	// non-trapping float-to-int conversions proposal
+	OpCodeMiscI32TruncSatF32s uint32 = 0x00
+	OpCodeMiscI32TruncSatF32u uint32 = 0x01
+	OpCodeMiscI32TruncSatF64s uint32 = 0x02
+	OpCodeMiscI32TruncSatF64u uint32 = 0x03
+	OpCodeMiscI64TruncSatF32s uint32 = 0x04
+	OpCodeMiscI64TruncSatF32u uint32 = 0x05
+	OpCodeMiscI64TruncSatF64s uint32 = 0x06
+	OpCodeMiscI64TruncSatF64u uint32 = 0x07
+
+	// bulk memory proposal
+	OpCodeMiscMemoryInit uint32 = 0x08
+	OpCodeMiscDataDrop   uint32 = 0x09
+	OpCodeMiscMemoryCopy uint32 = 0x0a
+	OpCodeMiscMemoryFill uint32 = 0x0b
+	OpCodeMiscTableInit  uint32 = 0x0c
+	OpCodeMiscElemDrop   uint32 = 0x0d
+	OpCodeMiscTableCopy  uint32 = 0x0e
+
+	// reference types proposal
+	OpCodeMiscTableGrow uint32 = 0x0f
+	OpCodeMiscTableSize uint32 = 0x10
+	OpCodeMiscTableFill uint32 = 0x11
+)
+
+// sub OpCode following OpCodePrefixSIMD, defined by SIMD proposal
+const (
+	OpCodeSIMDV128Const uint32 = 0x0c
+)
+
+// sub OpCode following OpCodePrefixAtomic, defined by threads proposal
+const (
+	OpCodeAtomicNotify uint32 = 0x00
+	OpCodeAtomicWait32 uint32 = 0x01
+	OpCodeAtomicWait64 uint32 = 0x02
 )
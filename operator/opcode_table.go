@@ -0,0 +1,520 @@
+// Code generated by gen.go from opcodes.txt; DO NOT EDIT.
+
+package operator
+
+import "github.com/LBruyne/wasm-decode/feature"
+
+var infos = []Info{
+	{OpCode: 0x00, Name: "unreachable", Dynamic: true},
+	{OpCode: 0x01, Name: "nop"},
+	{OpCode: 0x02, Name: "block", Imms: []Immediate{ImmBlockType}, Dynamic: true},
+	{OpCode: 0x03, Name: "loop", Imms: []Immediate{ImmBlockType}, Dynamic: true},
+	{OpCode: 0x04, Name: "if", Imms: []Immediate{ImmBlockType}, Dynamic: true},
+	{OpCode: 0x05, Name: "else", Dynamic: true},
+	{OpCode: 0x06, Name: "try", Imms: []Immediate{ImmBlockType}, Dynamic: true, Feature: feature.ExceptionHandling},
+	{OpCode: 0x07, Name: "catch", Imms: []Immediate{ImmTag}, Dynamic: true, Feature: feature.ExceptionHandling},
+	{OpCode: 0x08, Name: "throw", Imms: []Immediate{ImmTag}, Dynamic: true, Feature: feature.ExceptionHandling},
+	{OpCode: 0x09, Name: "rethrow", Imms: []Immediate{ImmLabel}, Dynamic: true, Feature: feature.ExceptionHandling},
+	{OpCode: 0x0b, Name: "end", Dynamic: true},
+	{OpCode: 0x0c, Name: "br", Imms: []Immediate{ImmLabel}, Dynamic: true},
+	{OpCode: 0x0d, Name: "br_if", Imms: []Immediate{ImmLabel}, Dynamic: true},
+	{OpCode: 0x0e, Name: "br_table", Imms: []Immediate{ImmLabels}, Dynamic: true},
+	{OpCode: 0x0f, Name: "return", Dynamic: true},
+	{OpCode: 0x10, Name: "call", Imms: []Immediate{ImmFunc}, Dynamic: true},
+	{OpCode: 0x11, Name: "call_indirect", Imms: []Immediate{ImmType, ImmTable}, Dynamic: true},
+	{OpCode: 0x12, Name: "return_call", Imms: []Immediate{ImmFunc}, Dynamic: true, Feature: feature.TailCall},
+	{OpCode: 0x13, Name: "return_call_indirect", Imms: []Immediate{ImmType, ImmTable}, Dynamic: true, Feature: feature.TailCall},
+	{OpCode: 0x18, Name: "delegate", Imms: []Immediate{ImmLabel}, Dynamic: true, Feature: feature.ExceptionHandling},
+	{OpCode: 0x19, Name: "catch_all", Dynamic: true, Feature: feature.ExceptionHandling},
+	{OpCode: 0x1a, Name: "drop", Dynamic: true},
+	{OpCode: 0x1b, Name: "select", Dynamic: true},
+	{OpCode: 0x1c, Name: "select", Imms: []Immediate{ImmValueTypes}, Dynamic: true, Feature: feature.ReferenceTypes},
+	{OpCode: 0x20, Name: "local.get", Imms: []Immediate{ImmLocal}, Dynamic: true},
+	{OpCode: 0x21, Name: "local.set", Imms: []Immediate{ImmLocal}, Dynamic: true},
+	{OpCode: 0x22, Name: "local.tee", Imms: []Immediate{ImmLocal}, Dynamic: true},
+	{OpCode: 0x23, Name: "global.get", Imms: []Immediate{ImmGlobal}, Dynamic: true, Const: true},
+	{OpCode: 0x24, Name: "global.set", Imms: []Immediate{ImmGlobal}, Dynamic: true},
+	{OpCode: 0x25, Name: "table.get", Imms: []Immediate{ImmTable}, Dynamic: true, Feature: feature.ReferenceTypes},
+	{OpCode: 0x26, Name: "table.set", Imms: []Immediate{ImmTable}, Dynamic: true, Feature: feature.ReferenceTypes},
+	{OpCode: 0x28, Name: "i32.load", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x29, Name: "i64.load", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI64}},
+	{OpCode: 0x2a, Name: "f32.load", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeF32}},
+	{OpCode: 0x2b, Name: "f64.load", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeF64}},
+	{OpCode: 0x2c, Name: "i32.load8_s", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x2d, Name: "i32.load8_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x2e, Name: "i32.load16_s", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x2f, Name: "i32.load16_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x30, Name: "i64.load8_s", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI64}},
+	{OpCode: 0x31, Name: "i64.load8_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI64}},
+	{OpCode: 0x32, Name: "i64.load16_s", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI64}},
+	{OpCode: 0x33, Name: "i64.load16_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI64}},
+	{OpCode: 0x34, Name: "i64.load32_s", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI64}},
+	{OpCode: 0x35, Name: "i64.load32_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI64}},
+	{OpCode: 0x36, Name: "i32.store", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}},
+	{OpCode: 0x37, Name: "i64.store", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}},
+	{OpCode: 0x38, Name: "f32.store", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeF32}},
+	{OpCode: 0x39, Name: "f64.store", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeF64}},
+	{OpCode: 0x3a, Name: "i32.store8", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}},
+	{OpCode: 0x3b, Name: "i32.store16", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}},
+	{OpCode: 0x3c, Name: "i64.store8", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}},
+	{OpCode: 0x3d, Name: "i64.store16", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}},
+	{OpCode: 0x3e, Name: "i64.store32", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}},
+	{OpCode: 0x3f, Name: "memory.size", Imms: []Immediate{ImmMemory}, Results: []byte{typeI32}},
+	{OpCode: 0x40, Name: "memory.grow", Imms: []Immediate{ImmMemory}, Params: []byte{typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x41, Name: "i32.const", Imms: []Immediate{ImmI32}, Results: []byte{typeI32}, Const: true},
+	{OpCode: 0x42, Name: "i64.const", Imms: []Immediate{ImmI64}, Results: []byte{typeI64}, Const: true},
+	{OpCode: 0x43, Name: "f32.const", Imms: []Immediate{ImmF32}, Results: []byte{typeF32}, Const: true},
+	{OpCode: 0x44, Name: "f64.const", Imms: []Immediate{ImmF64}, Results: []byte{typeF64}, Const: true},
+	{OpCode: 0x45, Name: "i32.eqz", Params: []byte{typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x46, Name: "i32.eq", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x47, Name: "i32.ne", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x48, Name: "i32.lt_s", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x49, Name: "i32.lt_u", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x4a, Name: "i32.gt_s", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x4b, Name: "i32.gt_u", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x4c, Name: "i32.le_s", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x4d, Name: "i32.le_u", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x4e, Name: "i32.ge_s", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x4f, Name: "i32.ge_u", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x50, Name: "i64.eqz", Params: []byte{typeI64}, Results: []byte{typeI32}},
+	{OpCode: 0x51, Name: "i64.eq", Params: []byte{typeI64, typeI64}, Results: []byte{typeI32}},
+	{OpCode: 0x52, Name: "i64.ne", Params: []byte{typeI64, typeI64}, Results: []byte{typeI32}},
+	{OpCode: 0x53, Name: "i64.lt_s", Params: []byte{typeI64, typeI64}, Results: []byte{typeI32}},
+	{OpCode: 0x54, Name: "i64.lt_u", Params: []byte{typeI64, typeI64}, Results: []byte{typeI32}},
+	{OpCode: 0x55, Name: "i64.gt_s", Params: []byte{typeI64, typeI64}, Results: []byte{typeI32}},
+	{OpCode: 0x56, Name: "i64.gt_u", Params: []byte{typeI64, typeI64}, Results: []byte{typeI32}},
+	{OpCode: 0x57, Name: "i64.le_s", Params: []byte{typeI64, typeI64}, Results: []byte{typeI32}},
+	{OpCode: 0x58, Name: "i64.le_u", Params: []byte{typeI64, typeI64}, Results: []byte{typeI32}},
+	{OpCode: 0x59, Name: "i64.ge_s", Params: []byte{typeI64, typeI64}, Results: []byte{typeI32}},
+	{OpCode: 0x5a, Name: "i64.ge_u", Params: []byte{typeI64, typeI64}, Results: []byte{typeI32}},
+	{OpCode: 0x5b, Name: "f32.eq", Params: []byte{typeF32, typeF32}, Results: []byte{typeI32}},
+	{OpCode: 0x5c, Name: "f32.ne", Params: []byte{typeF32, typeF32}, Results: []byte{typeI32}},
+	{OpCode: 0x5d, Name: "f32.lt", Params: []byte{typeF32, typeF32}, Results: []byte{typeI32}},
+	{OpCode: 0x5e, Name: "f32.gt", Params: []byte{typeF32, typeF32}, Results: []byte{typeI32}},
+	{OpCode: 0x5f, Name: "f32.le", Params: []byte{typeF32, typeF32}, Results: []byte{typeI32}},
+	{OpCode: 0x60, Name: "f32.ge", Params: []byte{typeF32, typeF32}, Results: []byte{typeI32}},
+	{OpCode: 0x61, Name: "f64.eq", Params: []byte{typeF64, typeF64}, Results: []byte{typeI32}},
+	{OpCode: 0x62, Name: "f64.ne", Params: []byte{typeF64, typeF64}, Results: []byte{typeI32}},
+	{OpCode: 0x63, Name: "f64.lt", Params: []byte{typeF64, typeF64}, Results: []byte{typeI32}},
+	{OpCode: 0x64, Name: "f64.gt", Params: []byte{typeF64, typeF64}, Results: []byte{typeI32}},
+	{OpCode: 0x65, Name: "f64.le", Params: []byte{typeF64, typeF64}, Results: []byte{typeI32}},
+	{OpCode: 0x66, Name: "f64.ge", Params: []byte{typeF64, typeF64}, Results: []byte{typeI32}},
+	{OpCode: 0x67, Name: "i32.clz", Params: []byte{typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x68, Name: "i32.ctz", Params: []byte{typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x69, Name: "i32.popcnt", Params: []byte{typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x6a, Name: "i32.add", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x6b, Name: "i32.sub", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x6c, Name: "i32.mul", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x6d, Name: "i32.div_s", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x6e, Name: "i32.div_u", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x6f, Name: "i32.rem_s", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x70, Name: "i32.rem_u", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x71, Name: "i32.and", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x72, Name: "i32.or", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x73, Name: "i32.xor", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x74, Name: "i32.shl", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x75, Name: "i32.shr_s", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x76, Name: "i32.shr_u", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x77, Name: "i32.rotl", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x78, Name: "i32.rotr", Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}},
+	{OpCode: 0x79, Name: "i64.clz", Params: []byte{typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x7a, Name: "i64.ctz", Params: []byte{typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x7b, Name: "i64.popcnt", Params: []byte{typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x7c, Name: "i64.add", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x7d, Name: "i64.sub", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x7e, Name: "i64.mul", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x7f, Name: "i64.div_s", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x80, Name: "i64.div_u", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x81, Name: "i64.rem_s", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x82, Name: "i64.rem_u", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x83, Name: "i64.and", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x84, Name: "i64.or", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x85, Name: "i64.xor", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x86, Name: "i64.shl", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x87, Name: "i64.shr_s", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x88, Name: "i64.shr_u", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x89, Name: "i64.rotl", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x8a, Name: "i64.rotr", Params: []byte{typeI64, typeI64}, Results: []byte{typeI64}},
+	{OpCode: 0x8b, Name: "f32.abs", Params: []byte{typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x8c, Name: "f32.neg", Params: []byte{typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x8d, Name: "f32.ceil", Params: []byte{typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x8e, Name: "f32.floor", Params: []byte{typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x8f, Name: "f32.trunc", Params: []byte{typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x90, Name: "f32.nearest", Params: []byte{typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x91, Name: "f32.sqrt", Params: []byte{typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x92, Name: "f32.add", Params: []byte{typeF32, typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x93, Name: "f32.sub", Params: []byte{typeF32, typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x94, Name: "f32.mul", Params: []byte{typeF32, typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x95, Name: "f32.div", Params: []byte{typeF32, typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x96, Name: "f32.min", Params: []byte{typeF32, typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x97, Name: "f32.max", Params: []byte{typeF32, typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x98, Name: "f32.copysign", Params: []byte{typeF32, typeF32}, Results: []byte{typeF32}},
+	{OpCode: 0x99, Name: "f64.abs", Params: []byte{typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0x9a, Name: "f64.neg", Params: []byte{typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0x9b, Name: "f64.ceil", Params: []byte{typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0x9c, Name: "f64.floor", Params: []byte{typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0x9d, Name: "f64.trunc", Params: []byte{typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0x9e, Name: "f64.nearest", Params: []byte{typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0x9f, Name: "f64.sqrt", Params: []byte{typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0xa0, Name: "f64.add", Params: []byte{typeF64, typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0xa1, Name: "f64.sub", Params: []byte{typeF64, typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0xa2, Name: "f64.mul", Params: []byte{typeF64, typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0xa3, Name: "f64.div", Params: []byte{typeF64, typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0xa4, Name: "f64.min", Params: []byte{typeF64, typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0xa5, Name: "f64.max", Params: []byte{typeF64, typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0xa6, Name: "f64.copysign", Params: []byte{typeF64, typeF64}, Results: []byte{typeF64}},
+	{OpCode: 0xa7, Name: "i32.wrap_i64", Params: []byte{typeI64}, Results: []byte{typeI32}},
+	{OpCode: 0xa8, Name: "i32.trunc_f32_s", Params: []byte{typeF32}, Results: []byte{typeI32}},
+	{OpCode: 0xa9, Name: "i32.trunc_f32_u", Params: []byte{typeF32}, Results: []byte{typeI32}},
+	{OpCode: 0xaa, Name: "i32.trunc_f64_s", Params: []byte{typeF64}, Results: []byte{typeI32}},
+	{OpCode: 0xab, Name: "i32.trunc_f64_u", Params: []byte{typeF64}, Results: []byte{typeI32}},
+	{OpCode: 0xac, Name: "i64.extend_i32_s", Params: []byte{typeI32}, Results: []byte{typeI64}},
+	{OpCode: 0xad, Name: "i64.extend_i32_u", Params: []byte{typeI32}, Results: []byte{typeI64}},
+	{OpCode: 0xae, Name: "i64.trunc_f32_s", Params: []byte{typeF32}, Results: []byte{typeI64}},
+	{OpCode: 0xaf, Name: "i64.trunc_f32_u", Params: []byte{typeF32}, Results: []byte{typeI64}},
+	{OpCode: 0xb0, Name: "i64.trunc_f64_s", Params: []byte{typeF64}, Results: []byte{typeI64}},
+	{OpCode: 0xb1, Name: "i64.trunc_f64_u", Params: []byte{typeF64}, Results: []byte{typeI64}},
+	{OpCode: 0xb2, Name: "f32.convert_i32_s", Params: []byte{typeI32}, Results: []byte{typeF32}},
+	{OpCode: 0xb3, Name: "f32.convert_i32_u", Params: []byte{typeI32}, Results: []byte{typeF32}},
+	{OpCode: 0xb4, Name: "f32.convert_i64_s", Params: []byte{typeI64}, Results: []byte{typeF32}},
+	{OpCode: 0xb5, Name: "f32.convert_i64_u", Params: []byte{typeI64}, Results: []byte{typeF32}},
+	{OpCode: 0xb6, Name: "f32.demote_f64", Params: []byte{typeF64}, Results: []byte{typeF32}},
+	{OpCode: 0xb7, Name: "f64.convert_i32_s", Params: []byte{typeI32}, Results: []byte{typeF64}},
+	{OpCode: 0xb8, Name: "f64.convert_i32_u", Params: []byte{typeI32}, Results: []byte{typeF64}},
+	{OpCode: 0xb9, Name: "f64.convert_i64_s", Params: []byte{typeI64}, Results: []byte{typeF64}},
+	{OpCode: 0xba, Name: "f64.convert_i64_u", Params: []byte{typeI64}, Results: []byte{typeF64}},
+	{OpCode: 0xbb, Name: "f64.promote_f32", Params: []byte{typeF32}, Results: []byte{typeF64}},
+	{OpCode: 0xbc, Name: "i32.reinterpret_f32", Params: []byte{typeF32}, Results: []byte{typeI32}},
+	{OpCode: 0xbd, Name: "i64.reinterpret_f64", Params: []byte{typeF64}, Results: []byte{typeI64}},
+	{OpCode: 0xbe, Name: "f32.reinterpret_i32", Params: []byte{typeI32}, Results: []byte{typeF32}},
+	{OpCode: 0xbf, Name: "f64.reinterpret_i64", Params: []byte{typeI64}, Results: []byte{typeF64}},
+	{OpCode: 0xc0, Name: "i32.extend8_s", Params: []byte{typeI32}, Results: []byte{typeI32}, Feature: feature.SignExtension},
+	{OpCode: 0xc1, Name: "i32.extend16_s", Params: []byte{typeI32}, Results: []byte{typeI32}, Feature: feature.SignExtension},
+	{OpCode: 0xc2, Name: "i64.extend8_s", Params: []byte{typeI64}, Results: []byte{typeI64}, Feature: feature.SignExtension},
+	{OpCode: 0xc3, Name: "i64.extend16_s", Params: []byte{typeI64}, Results: []byte{typeI64}, Feature: feature.SignExtension},
+	{OpCode: 0xc4, Name: "i64.extend32_s", Params: []byte{typeI64}, Results: []byte{typeI64}, Feature: feature.SignExtension},
+	{OpCode: 0xd0, Name: "ref.null", Imms: []Immediate{ImmRefType}, Dynamic: true, Feature: feature.ReferenceTypes, Const: true},
+	{OpCode: 0xd1, Name: "ref.is_null", Dynamic: true, Feature: feature.ReferenceTypes},
+	{OpCode: 0xd2, Name: "ref.func", Imms: []Immediate{ImmFunc}, Results: []byte{typeFuncRef}, Feature: feature.ReferenceTypes, Const: true},
+	{OpCode: OpCodePrefixMisc, Sub: 0x00, Name: "i32.trunc_sat_f32_s", Params: []byte{typeF32}, Results: []byte{typeI32}, Feature: feature.NonTrappingFloatToInt},
+	{OpCode: OpCodePrefixMisc, Sub: 0x01, Name: "i32.trunc_sat_f32_u", Params: []byte{typeF32}, Results: []byte{typeI32}, Feature: feature.NonTrappingFloatToInt},
+	{OpCode: OpCodePrefixMisc, Sub: 0x02, Name: "i32.trunc_sat_f64_s", Params: []byte{typeF64}, Results: []byte{typeI32}, Feature: feature.NonTrappingFloatToInt},
+	{OpCode: OpCodePrefixMisc, Sub: 0x03, Name: "i32.trunc_sat_f64_u", Params: []byte{typeF64}, Results: []byte{typeI32}, Feature: feature.NonTrappingFloatToInt},
+	{OpCode: OpCodePrefixMisc, Sub: 0x04, Name: "i64.trunc_sat_f32_s", Params: []byte{typeF32}, Results: []byte{typeI64}, Feature: feature.NonTrappingFloatToInt},
+	{OpCode: OpCodePrefixMisc, Sub: 0x05, Name: "i64.trunc_sat_f32_u", Params: []byte{typeF32}, Results: []byte{typeI64}, Feature: feature.NonTrappingFloatToInt},
+	{OpCode: OpCodePrefixMisc, Sub: 0x06, Name: "i64.trunc_sat_f64_s", Params: []byte{typeF64}, Results: []byte{typeI64}, Feature: feature.NonTrappingFloatToInt},
+	{OpCode: OpCodePrefixMisc, Sub: 0x07, Name: "i64.trunc_sat_f64_u", Params: []byte{typeF64}, Results: []byte{typeI64}, Feature: feature.NonTrappingFloatToInt},
+	{OpCode: OpCodePrefixMisc, Sub: 0x08, Name: "memory.init", Imms: []Immediate{ImmData, ImmMemory}, Params: []byte{typeI32, typeI32, typeI32}, Feature: feature.BulkMemory},
+	{OpCode: OpCodePrefixMisc, Sub: 0x09, Name: "data.drop", Imms: []Immediate{ImmData}, Feature: feature.BulkMemory},
+	{OpCode: OpCodePrefixMisc, Sub: 0x0a, Name: "memory.copy", Imms: []Immediate{ImmMemory, ImmMemory}, Params: []byte{typeI32, typeI32, typeI32}, Feature: feature.BulkMemory},
+	{OpCode: OpCodePrefixMisc, Sub: 0x0b, Name: "memory.fill", Imms: []Immediate{ImmMemory}, Params: []byte{typeI32, typeI32, typeI32}, Feature: feature.BulkMemory},
+	{OpCode: OpCodePrefixMisc, Sub: 0x0c, Name: "table.init", Imms: []Immediate{ImmElem, ImmTable}, Params: []byte{typeI32, typeI32, typeI32}, Feature: feature.BulkMemory},
+	{OpCode: OpCodePrefixMisc, Sub: 0x0d, Name: "elem.drop", Imms: []Immediate{ImmElem}, Feature: feature.BulkMemory},
+	{OpCode: OpCodePrefixMisc, Sub: 0x0e, Name: "table.copy", Imms: []Immediate{ImmTable, ImmTable}, Params: []byte{typeI32, typeI32, typeI32}, Feature: feature.BulkMemory},
+	{OpCode: OpCodePrefixMisc, Sub: 0x0f, Name: "table.grow", Imms: []Immediate{ImmTable}, Dynamic: true, Feature: feature.ReferenceTypes},
+	{OpCode: OpCodePrefixMisc, Sub: 0x10, Name: "table.size", Imms: []Immediate{ImmTable}, Results: []byte{typeI32}, Feature: feature.ReferenceTypes},
+	{OpCode: OpCodePrefixMisc, Sub: 0x11, Name: "table.fill", Imms: []Immediate{ImmTable}, Dynamic: true, Feature: feature.ReferenceTypes},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x00, Name: "v128.load", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x01, Name: "v128.load8x8_s", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x02, Name: "v128.load8x8_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x03, Name: "v128.load16x4_s", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x04, Name: "v128.load16x4_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x05, Name: "v128.load32x2_s", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x06, Name: "v128.load32x2_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x07, Name: "v128.load8_splat", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x08, Name: "v128.load16_splat", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x09, Name: "v128.load32_splat", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x0a, Name: "v128.load64_splat", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x0b, Name: "v128.store", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x0c, Name: "v128.const", Imms: []Immediate{ImmV128}, Results: []byte{typeV128}, Feature: feature.SIMD, Const: true},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x0d, Name: "i8x16.shuffle", Imms: []Immediate{ImmLanes16}, Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x0e, Name: "i8x16.swizzle", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x0f, Name: "i8x16.splat", Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x10, Name: "i16x8.splat", Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x11, Name: "i32x4.splat", Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x12, Name: "i64x2.splat", Params: []byte{typeI64}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x13, Name: "f32x4.splat", Params: []byte{typeF32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x14, Name: "f64x2.splat", Params: []byte{typeF64}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x15, Name: "i8x16.extract_lane_s", Imms: []Immediate{ImmLane}, Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x16, Name: "i8x16.extract_lane_u", Imms: []Immediate{ImmLane}, Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x17, Name: "i8x16.replace_lane", Imms: []Immediate{ImmLane}, Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x18, Name: "i16x8.extract_lane_s", Imms: []Immediate{ImmLane}, Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x19, Name: "i16x8.extract_lane_u", Imms: []Immediate{ImmLane}, Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x1a, Name: "i16x8.replace_lane", Imms: []Immediate{ImmLane}, Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x1b, Name: "i32x4.extract_lane", Imms: []Immediate{ImmLane}, Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x1c, Name: "i32x4.replace_lane", Imms: []Immediate{ImmLane}, Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x1d, Name: "i64x2.extract_lane", Imms: []Immediate{ImmLane}, Params: []byte{typeV128}, Results: []byte{typeI64}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x1e, Name: "i64x2.replace_lane", Imms: []Immediate{ImmLane}, Params: []byte{typeV128, typeI64}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x1f, Name: "f32x4.extract_lane", Imms: []Immediate{ImmLane}, Params: []byte{typeV128}, Results: []byte{typeF32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x20, Name: "f32x4.replace_lane", Imms: []Immediate{ImmLane}, Params: []byte{typeV128, typeF32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x21, Name: "f64x2.extract_lane", Imms: []Immediate{ImmLane}, Params: []byte{typeV128}, Results: []byte{typeF64}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x22, Name: "f64x2.replace_lane", Imms: []Immediate{ImmLane}, Params: []byte{typeV128, typeF64}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x23, Name: "i8x16.eq", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x24, Name: "i8x16.ne", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x25, Name: "i8x16.lt_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x26, Name: "i8x16.lt_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x27, Name: "i8x16.gt_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x28, Name: "i8x16.gt_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x29, Name: "i8x16.le_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x2a, Name: "i8x16.le_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x2b, Name: "i8x16.ge_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x2c, Name: "i8x16.ge_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x2d, Name: "i16x8.eq", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x2e, Name: "i16x8.ne", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x2f, Name: "i16x8.lt_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x30, Name: "i16x8.lt_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x31, Name: "i16x8.gt_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x32, Name: "i16x8.gt_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x33, Name: "i16x8.le_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x34, Name: "i16x8.le_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x35, Name: "i16x8.ge_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x36, Name: "i16x8.ge_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x37, Name: "i32x4.eq", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x38, Name: "i32x4.ne", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x39, Name: "i32x4.lt_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x3a, Name: "i32x4.lt_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x3b, Name: "i32x4.gt_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x3c, Name: "i32x4.gt_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x3d, Name: "i32x4.le_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x3e, Name: "i32x4.le_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x3f, Name: "i32x4.ge_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x40, Name: "i32x4.ge_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x41, Name: "f32x4.eq", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x42, Name: "f32x4.ne", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x43, Name: "f32x4.lt", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x44, Name: "f32x4.gt", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x45, Name: "f32x4.le", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x46, Name: "f32x4.ge", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x47, Name: "f64x2.eq", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x48, Name: "f64x2.ne", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x49, Name: "f64x2.lt", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x4a, Name: "f64x2.gt", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x4b, Name: "f64x2.le", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x4c, Name: "f64x2.ge", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x4d, Name: "v128.not", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x4e, Name: "v128.and", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x4f, Name: "v128.andnot", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x50, Name: "v128.or", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x51, Name: "v128.xor", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x52, Name: "v128.bitselect", Params: []byte{typeV128, typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x53, Name: "v128.any_true", Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x54, Name: "v128.load8_lane", Imms: []Immediate{ImmMemArg, ImmLane}, Params: []byte{typeI32, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x55, Name: "v128.load16_lane", Imms: []Immediate{ImmMemArg, ImmLane}, Params: []byte{typeI32, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x56, Name: "v128.load32_lane", Imms: []Immediate{ImmMemArg, ImmLane}, Params: []byte{typeI32, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x57, Name: "v128.load64_lane", Imms: []Immediate{ImmMemArg, ImmLane}, Params: []byte{typeI32, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x58, Name: "v128.store8_lane", Imms: []Immediate{ImmMemArg, ImmLane}, Params: []byte{typeI32, typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x59, Name: "v128.store16_lane", Imms: []Immediate{ImmMemArg, ImmLane}, Params: []byte{typeI32, typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x5a, Name: "v128.store32_lane", Imms: []Immediate{ImmMemArg, ImmLane}, Params: []byte{typeI32, typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x5b, Name: "v128.store64_lane", Imms: []Immediate{ImmMemArg, ImmLane}, Params: []byte{typeI32, typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x5c, Name: "v128.load32_zero", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x5d, Name: "v128.load64_zero", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x5e, Name: "f32x4.demote_f64x2_zero", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x5f, Name: "f64x2.promote_low_f32x4", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x60, Name: "i8x16.abs", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x61, Name: "i8x16.neg", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x62, Name: "i8x16.popcnt", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x63, Name: "i8x16.all_true", Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x64, Name: "i8x16.bitmask", Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x65, Name: "i8x16.narrow_i16x8_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x66, Name: "i8x16.narrow_i16x8_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x67, Name: "f32x4.ceil", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x68, Name: "f32x4.floor", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x69, Name: "f32x4.trunc", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x6a, Name: "f32x4.nearest", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x6b, Name: "i8x16.shl", Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x6c, Name: "i8x16.shr_s", Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x6d, Name: "i8x16.shr_u", Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x6e, Name: "i8x16.add", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x6f, Name: "i8x16.add_sat_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x70, Name: "i8x16.add_sat_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x71, Name: "i8x16.sub", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x72, Name: "i8x16.sub_sat_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x73, Name: "i8x16.sub_sat_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x74, Name: "f64x2.ceil", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x75, Name: "f64x2.floor", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x76, Name: "i8x16.min_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x77, Name: "i8x16.min_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x78, Name: "i8x16.max_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x79, Name: "i8x16.max_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x7a, Name: "f64x2.trunc", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x7b, Name: "i8x16.avgr_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x7c, Name: "i16x8.extadd_pairwise_i8x16_s", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x7d, Name: "i16x8.extadd_pairwise_i8x16_u", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x7e, Name: "i32x4.extadd_pairwise_i16x8_s", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x7f, Name: "i32x4.extadd_pairwise_i16x8_u", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x80, Name: "i16x8.abs", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x81, Name: "i16x8.neg", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x82, Name: "i16x8.q15mulr_sat_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x83, Name: "i16x8.all_true", Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x84, Name: "i16x8.bitmask", Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x85, Name: "i16x8.narrow_i32x4_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x86, Name: "i16x8.narrow_i32x4_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x87, Name: "i16x8.extend_low_i8x16_s", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x88, Name: "i16x8.extend_high_i8x16_s", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x89, Name: "i16x8.extend_low_i8x16_u", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x8a, Name: "i16x8.extend_high_i8x16_u", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x8b, Name: "i16x8.shl", Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x8c, Name: "i16x8.shr_s", Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x8d, Name: "i16x8.shr_u", Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x8e, Name: "i16x8.add", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x8f, Name: "i16x8.add_sat_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x90, Name: "i16x8.add_sat_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x91, Name: "i16x8.sub", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x92, Name: "i16x8.sub_sat_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x93, Name: "i16x8.sub_sat_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x94, Name: "f64x2.nearest", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x95, Name: "i16x8.mul", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x96, Name: "i16x8.min_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x97, Name: "i16x8.min_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x98, Name: "i16x8.max_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x99, Name: "i16x8.max_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x9b, Name: "i16x8.avgr_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x9c, Name: "i16x8.extmul_low_i8x16_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x9d, Name: "i16x8.extmul_high_i8x16_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x9e, Name: "i16x8.extmul_low_i8x16_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0x9f, Name: "i16x8.extmul_high_i8x16_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xa0, Name: "i32x4.abs", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xa1, Name: "i32x4.neg", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xa3, Name: "i32x4.all_true", Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xa4, Name: "i32x4.bitmask", Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xa7, Name: "i32x4.extend_low_i16x8_s", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xa8, Name: "i32x4.extend_high_i16x8_s", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xa9, Name: "i32x4.extend_low_i16x8_u", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xaa, Name: "i32x4.extend_high_i16x8_u", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xab, Name: "i32x4.shl", Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xac, Name: "i32x4.shr_s", Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xad, Name: "i32x4.shr_u", Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xae, Name: "i32x4.add", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xb1, Name: "i32x4.sub", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xb5, Name: "i32x4.mul", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xb6, Name: "i32x4.min_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xb7, Name: "i32x4.min_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xb8, Name: "i32x4.max_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xb9, Name: "i32x4.max_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xba, Name: "i32x4.dot_i16x8_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xbc, Name: "i32x4.extmul_low_i16x8_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xbd, Name: "i32x4.extmul_high_i16x8_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xbe, Name: "i32x4.extmul_low_i16x8_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xbf, Name: "i32x4.extmul_high_i16x8_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xc0, Name: "i64x2.abs", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xc1, Name: "i64x2.neg", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xc3, Name: "i64x2.all_true", Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xc4, Name: "i64x2.bitmask", Params: []byte{typeV128}, Results: []byte{typeI32}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xc7, Name: "i64x2.extend_low_i32x4_s", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xc8, Name: "i64x2.extend_high_i32x4_s", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xc9, Name: "i64x2.extend_low_i32x4_u", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xca, Name: "i64x2.extend_high_i32x4_u", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xcb, Name: "i64x2.shl", Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xcc, Name: "i64x2.shr_s", Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xcd, Name: "i64x2.shr_u", Params: []byte{typeV128, typeI32}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xce, Name: "i64x2.add", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xd1, Name: "i64x2.sub", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xd5, Name: "i64x2.mul", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xd6, Name: "i64x2.eq", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xd7, Name: "i64x2.ne", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xd8, Name: "i64x2.lt_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xd9, Name: "i64x2.gt_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xda, Name: "i64x2.le_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xdb, Name: "i64x2.ge_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xdc, Name: "i64x2.extmul_low_i32x4_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xdd, Name: "i64x2.extmul_high_i32x4_s", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xde, Name: "i64x2.extmul_low_i32x4_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xdf, Name: "i64x2.extmul_high_i32x4_u", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xe0, Name: "f32x4.abs", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xe1, Name: "f32x4.neg", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xe3, Name: "f32x4.sqrt", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xe4, Name: "f32x4.add", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xe5, Name: "f32x4.sub", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xe6, Name: "f32x4.mul", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xe7, Name: "f32x4.div", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xe8, Name: "f32x4.min", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xe9, Name: "f32x4.max", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xea, Name: "f32x4.pmin", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xeb, Name: "f32x4.pmax", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xec, Name: "f64x2.abs", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xed, Name: "f64x2.neg", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xef, Name: "f64x2.sqrt", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xf0, Name: "f64x2.add", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xf1, Name: "f64x2.sub", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xf2, Name: "f64x2.mul", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xf3, Name: "f64x2.div", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xf4, Name: "f64x2.min", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xf5, Name: "f64x2.max", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xf6, Name: "f64x2.pmin", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xf7, Name: "f64x2.pmax", Params: []byte{typeV128, typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xf8, Name: "i32x4.trunc_sat_f32x4_s", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xf9, Name: "i32x4.trunc_sat_f32x4_u", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xfa, Name: "f32x4.convert_i32x4_s", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xfb, Name: "f32x4.convert_i32x4_u", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xfc, Name: "i32x4.trunc_sat_f64x2_s_zero", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xfd, Name: "i32x4.trunc_sat_f64x2_u_zero", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xfe, Name: "f64x2.convert_low_i32x4_s", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixSIMD, Sub: 0xff, Name: "f64x2.convert_low_i32x4_u", Params: []byte{typeV128}, Results: []byte{typeV128}, Feature: feature.SIMD},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x00, Name: "memory.atomic.notify", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x01, Name: "memory.atomic.wait32", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32, typeI64}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x02, Name: "memory.atomic.wait64", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64, typeI64}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x03, Name: "atomic.fence", Imms: []Immediate{ImmByte}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x10, Name: "i32.atomic.load", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x11, Name: "i64.atomic.load", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x12, Name: "i32.atomic.load8_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x13, Name: "i32.atomic.load16_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x14, Name: "i64.atomic.load8_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x15, Name: "i64.atomic.load16_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x16, Name: "i64.atomic.load32_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x17, Name: "i32.atomic.store", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x18, Name: "i64.atomic.store", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x19, Name: "i32.atomic.store8", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x1a, Name: "i32.atomic.store16", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x1b, Name: "i64.atomic.store8", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x1c, Name: "i64.atomic.store16", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x1d, Name: "i64.atomic.store32", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x1e, Name: "i32.atomic.rmw.add", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x1f, Name: "i64.atomic.rmw.add", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x20, Name: "i32.atomic.rmw8.add_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x21, Name: "i32.atomic.rmw16.add_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x22, Name: "i64.atomic.rmw8.add_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x23, Name: "i64.atomic.rmw16.add_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x24, Name: "i64.atomic.rmw32.add_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x25, Name: "i32.atomic.rmw.sub", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x26, Name: "i64.atomic.rmw.sub", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x27, Name: "i32.atomic.rmw8.sub_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x28, Name: "i32.atomic.rmw16.sub_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x29, Name: "i64.atomic.rmw8.sub_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x2a, Name: "i64.atomic.rmw16.sub_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x2b, Name: "i64.atomic.rmw32.sub_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x2c, Name: "i32.atomic.rmw.and", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x2d, Name: "i64.atomic.rmw.and", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x2e, Name: "i32.atomic.rmw8.and_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x2f, Name: "i32.atomic.rmw16.and_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x30, Name: "i64.atomic.rmw8.and_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x31, Name: "i64.atomic.rmw16.and_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x32, Name: "i64.atomic.rmw32.and_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x33, Name: "i32.atomic.rmw.or", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x34, Name: "i64.atomic.rmw.or", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x35, Name: "i32.atomic.rmw8.or_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x36, Name: "i32.atomic.rmw16.or_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x37, Name: "i64.atomic.rmw8.or_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x38, Name: "i64.atomic.rmw16.or_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x39, Name: "i64.atomic.rmw32.or_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x3a, Name: "i32.atomic.rmw.xor", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x3b, Name: "i64.atomic.rmw.xor", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x3c, Name: "i32.atomic.rmw8.xor_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x3d, Name: "i32.atomic.rmw16.xor_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x3e, Name: "i64.atomic.rmw8.xor_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x3f, Name: "i64.atomic.rmw16.xor_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x40, Name: "i64.atomic.rmw32.xor_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x41, Name: "i32.atomic.rmw.xchg", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x42, Name: "i64.atomic.rmw.xchg", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x43, Name: "i32.atomic.rmw8.xchg_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x44, Name: "i32.atomic.rmw16.xchg_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x45, Name: "i64.atomic.rmw8.xchg_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x46, Name: "i64.atomic.rmw16.xchg_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x47, Name: "i64.atomic.rmw32.xchg_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x48, Name: "i32.atomic.rmw.cmpxchg", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x49, Name: "i64.atomic.rmw.cmpxchg", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x4a, Name: "i32.atomic.rmw8.cmpxchg_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x4b, Name: "i32.atomic.rmw16.cmpxchg_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI32, typeI32}, Results: []byte{typeI32}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x4c, Name: "i64.atomic.rmw8.cmpxchg_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x4d, Name: "i64.atomic.rmw16.cmpxchg_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+	{OpCode: OpCodePrefixAtomic, Sub: 0x4e, Name: "i64.atomic.rmw32.cmpxchg_u", Imms: []Immediate{ImmMemArg}, Params: []byte{typeI32, typeI64, typeI64}, Results: []byte{typeI64}, Feature: feature.Threads},
+}
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CustomSectionDecoder parses the bytes of a custom section, following its name, into a typed value
+type CustomSectionDecoder func(data []byte) (interface{}, error)
+
+var (
+	customDecodersMu sync.RWMutex
+	customDecoders   = map[string]CustomSectionDecoder{
+		"name":            decodeNameSection,
+		"producers":       decodeProducersSection,
+		"target_features": decodeTargetFeaturesSection,
+	}
+)
+
+// RegisterCustomSectionDecoder registers the decoder of the custom sections of the given name for all decodings,
+// it replaces the previous one of the name, and a nil decoder removes it.
+// Decoders of "name", "producers" and "target_features" sections are registered by default.
+func RegisterCustomSectionDecoder(name string, fn CustomSectionDecoder) {
+	customDecodersMu.Lock()
+	defer customDecodersMu.Unlock()
+
+	if fn == nil {
+		delete(customDecoders, name)
+		return
+	}
+	customDecoders[name] = fn
+}
+
+// customSectionDecoder returns the decoder of the custom sections of the given name, or nil.
+// Decoders of DecodeOptions take precedence over registered ones.
+func customSectionDecoder(opts *DecodeOptions, name string) CustomSectionDecoder {
+	if fn, ok := opts.CustomSectionDecoders[name]; ok {
+		return fn
+	}
+
+	customDecodersMu.RLock()
+	defer customDecodersMu.RUnlock()
+	return customDecoders[name]
+}
+
+// decodeCustomSection parses sec with its decoder if any. The error is kept in sec unless
+// DecodeOptions.StrictCustomSections is set, since custom sections do not affect the semantics of a module.
+func (r *reader) decodeCustomSection(sec *CustomSec) error {
+	fn := customSectionDecoder(r.opts, sec.Name)
+	if fn == nil {
+		return nil
+	}
+
+	v, err := fn(sec.Bytes)
+	if err != nil {
+		if r.opts.StrictCustomSections {
+			return fmt.Errorf("decode custom section %q: %w", sec.Name, err)
+		}
+		sec.Err = err
+		return nil
+	}
+	sec.Value = v
+	return nil
+}
+
+// CustomSection returns the first custom section of the given name, or nil
+func (m *Module) CustomSection(name string) *CustomSec {
+	for _, c := range m.SecCustoms {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// NameSection returns the decoded "name" section, or nil if there is none or it is malformed
+func (m *Module) NameSection() *NameSection {
+	if c := m.CustomSection("name"); c != nil {
+		ns, _ := c.Value.(*NameSection)
+		return ns
+	}
+	return nil
+}
+
+// ProducersSection returns the decoded "producers" section, or nil if there is none or it is malformed
+func (m *Module) ProducersSection() *ProducersSection {
+	if c := m.CustomSection("producers"); c != nil {
+		ps, _ := c.Value.(*ProducersSection)
+		return ps
+	}
+	return nil
+}
+
+// TargetFeaturesSection returns the decoded "target_features" section, or nil if there is none or it is malformed
+func (m *Module) TargetFeaturesSection() *TargetFeaturesSection {
+	if c := m.CustomSection("target_features"); c != nil {
+		ts, _ := c.Value.(*TargetFeaturesSection)
+		return ts
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package types
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// NameMap maps indices to names
+type NameMap map[uint32]string
+
+// NameSection is the "name" custom section, which names the module, its functions and their locals for debuggers
+type NameSection struct {
+	Module    string
+	Functions NameMap
+	Locals    map[uint32]NameMap // names of locals by function index
+}
+
+const (
+	nameSubsectionModule   = 0
+	nameSubsectionFunction = 1
+	nameSubsectionLocal    = 2
+)
+
+func decodeNameSection(data []byte) (interface{}, error) {
+	r := newBytesReader(data, nil)
+	if err := r.internStrings(uint32(len(data))); err != nil {
+		return nil, err
+	}
+	ret := &NameSection{}
+
+	last := -1
+	for r.off < int64(len(data)) {
+		id, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("read subsection id: %w", err)
+		}
+		if int(id) <= last {
+			return nil, fmt.Errorf("subsection %d out of order", id)
+		}
+		last = int(id)
+
+		size, err := r.readUint32()
+		if err != nil {
+			return nil, fmt.Errorf("read size of subsection %d: %w", id, err)
+		}
+		end := r.off + int64(size)
+		if end > int64(len(data)) {
+			return nil, fmt.Errorf("subsection %d of %d bytes exceeds the section", id, size)
+		}
+
+		switch id {
+		case nameSubsectionModule:
+			ret.Module, _, err = r.readName()
+		case nameSubsectionFunction:
+			ret.Functions, err = readNameMap(r)
+		case nameSubsectionLocal:
+			ret.Locals, err = readIndirectNameMap(r)
+		default:
+			// subsections of extended name section proposal are skipped
+			err = r.skip(size)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read subsection %d: %w", id, err)
+		}
+		if r.off != end {
+			return nil, fmt.Errorf("subsection %d: %d bytes read but its size is %d", id, r.off-end+int64(size), size)
+		}
+	}
+	return ret, nil
+}
+
+// readNameMap read a vector of index and name pairs, whose indices are increasing
+func readNameMap(r *reader) (NameMap, error) {
+	vs, err := r.readVectorSize(unsafe.Sizeof(uint32(0)) + unsafe.Sizeof(""))
+	if err != nil {
+		return nil, fmt.Errorf("get size of name map: %w", err)
+	}
+
+	ret := make(NameMap, vs)
+	for i := uint32(0); i < vs; i++ {
+		idx, err := r.readUint32()
+		if err != nil {
+			return nil, fmt.Errorf("read %d-th index: %w", i, err)
+		}
+		if _, ok := ret[idx]; ok {
+			return nil, fmt.Errorf("duplicate index %d", idx)
+		}
+
+		ret[idx], _, err = r.readName()
+		if err != nil {
+			return nil, fmt.Errorf("read name of index %d: %w", idx, err)
+		}
+	}
+	return ret, nil
+}
+
+// readIndirectNameMap read a vector of index and name map pairs
+func readIndirectNameMap(r *reader) (map[uint32]NameMap, error) {
+	vs, err := r.readVectorSize(unsafe.Sizeof(uint32(0)) + unsafe.Sizeof(NameMap(nil)))
+	if err != nil {
+		return nil, fmt.Errorf("get size of indirect name map: %w", err)
+	}
+
+	ret := make(map[uint32]NameMap, vs)
+	for i := uint32(0); i < vs; i++ {
+		idx, err := r.readUint32()
+		if err != nil {
+			return nil, fmt.Errorf("read %d-th index: %w", i, err)
+		}
+		if _, ok := ret[idx]; ok {
+			return nil, fmt.Errorf("duplicate index %d", idx)
+		}
+
+		ret[idx], err = readNameMap(r)
+		if err != nil {
+			return nil, fmt.Errorf("read name map of index %d: %w", idx, err)
+		}
+	}
+	return ret, nil
+}
+
+// ProducersSection is the "producers" custom section, which records the tools producing the module
+type ProducersSection struct {
+	Fields []*ProducerField
+}
+
+// ProducerField is a field of producers section, such as "language", "processed-by" or "sdk"
+type ProducerField struct {
+	Name   string
+	Values []ProducerValue
+}
+
+// ProducerValue is a tool and its version
+type ProducerValue struct {
+	Name    string
+	Version string
+}
+
+// Field returns the values of the field of the given name
+func (ps *ProducersSection) Field(name string) []ProducerValue {
+	for _, f := range ps.Fields {
+		if f.Name == name {
+			return f.Values
+		}
+	}
+	return nil
+}
+
+func decodeProducersSection(data []byte) (interface{}, error) {
+	r := newBytesReader(data, nil)
+	if err := r.internStrings(uint32(len(data))); err != nil {
+		return nil, err
+	}
+	fs, err := r.readVectorSize(unsafe.Sizeof(ProducerField{}))
+	if err != nil {
+		return nil, fmt.Errorf("get number of fields: %w", err)
+	}
+
+	ret := &ProducersSection{Fields: make([]*ProducerField, fs)}
+	for i := range ret.Fields {
+		f := &ProducerField{}
+		if f.Name, _, err = r.readName(); err != nil {
+			return nil, fmt.Errorf("read name of %d-th field: %w", i, err)
+		}
+
+		vs, err := r.readVectorSize(unsafe.Sizeof(ProducerValue{}))
+		if err != nil {
+			return nil, fmt.Errorf("get number of values of field %s: %w", f.Name, err)
+		}
+		f.Values = make([]ProducerValue, vs)
+		for j := range f.Values {
+			if f.Values[j].Name, _, err = r.readName(); err != nil {
+				return nil, fmt.Errorf("read %d-th value of field %s: %w", j, f.Name, err)
+			}
+			if f.Values[j].Version, _, err = r.readName(); err != nil {
+				return nil, fmt.Errorf("read version of %d-th value of field %s: %w", j, f.Name, err)
+			}
+		}
+		ret.Fields[i] = f
+	}
+
+	if int(r.off) != len(data) {
+		return nil, fmt.Errorf("%d trailing bytes", len(data)-int(r.off))
+	}
+	return ret, nil
+}
+
+// TargetFeaturesSection is the "target_features" custom section, which declares the features
+// a module is compiled with
+type TargetFeaturesSection struct {
+	Features []TargetFeature
+}
+
+// TargetFeature is a feature declared by target_features section
+type TargetFeature struct {
+	// Prefix is '+' if the feature is used, '-' if it must not be and '=' if it is required
+	Prefix byte
+	Name   string
+}
+
+func decodeTargetFeaturesSection(data []byte) (interface{}, error) {
+	r := newBytesReader(data, nil)
+	if err := r.internStrings(uint32(len(data))); err != nil {
+		return nil, err
+	}
+	n, err := r.readVectorSize(unsafe.Sizeof(TargetFeature{}))
+	if err != nil {
+		return nil, fmt.Errorf("get number of features: %w", err)
+	}
+
+	ret := &TargetFeaturesSection{Features: make([]TargetFeature, n)}
+	for i := range ret.Features {
+		tf := &ret.Features[i]
+		if tf.Prefix, err = r.ReadByte(); err != nil {
+			return nil, fmt.Errorf("read prefix of %d-th feature: %w", i, err)
+		}
+		switch tf.Prefix {
+		case '+', '-', '=':
+		default:
+			return nil, fmt.Errorf("invalid prefix of %d-th feature: %#x", i, tf.Prefix)
+		}
+
+		if tf.Name, _, err = r.readName(); err != nil {
+			return nil, fmt.Errorf("read name of %d-th feature: %w", i, err)
+		}
+	}
+
+	if int(r.off) != len(data) {
+		return nil, fmt.Errorf("%d trailing bytes", len(data)-int(r.off))
+	}
+	return ret, nil
+}
@@ -0,0 +1,427 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/operator"
+	"github.com/LBruyne/wasm-decode/params"
+)
+
+// writer appends the binary format of a module to buf
+type writer struct {
+	buf []byte
+}
+
+func (w *writer) byte(b byte) {
+	w.buf = append(w.buf, b)
+}
+
+func (w *writer) uint32(v uint32) {
+	w.buf = append(w.buf, common.EncodeUint32(v)...)
+}
+
+func (w *writer) bytes(b []byte) {
+	w.uint32(uint32(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *writer) name(s string) {
+	w.uint32(uint32(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+// section writes the section of id whose payload is written by f, prefixed by its size
+func (w *writer) section(id SectionID, f func(w *writer) error) error {
+	payload := &writer{}
+	if err := f(payload); err != nil {
+		return fmt.Errorf("write section for %d: %w", id, err)
+	}
+
+	w.byte(byte(id))
+	w.bytes(payload.buf)
+	return nil
+}
+
+// encodedSection is a standard section written by Encode
+type encodedSection struct {
+	id    SectionID
+	empty bool
+	write func(w *writer) error
+}
+
+// encodedSections returns the standard sections of m in the order of the binary format
+func (m *Module) encodedSections() []encodedSection {
+	return []encodedSection{
+		{SectionIDType, len(m.SecType) == 0, m.writeSectionType},
+		{SectionIDImport, len(m.SecImport) == 0, m.writeSectionImport},
+		{SectionIDFunction, len(m.SecFunction) == 0, m.writeSectionFunction},
+		{SectionIDTable, len(m.SecTable) == 0, m.writeSectionTable},
+		{SectionIDMemory, len(m.SecMemory) == 0, m.writeSectionMemory},
+		{SectionIDGlobal, len(m.SecGlobal) == 0, m.writeSectionGlobal},
+		{SectionIDExport, len(m.SecExport) == 0, m.writeSectionExport},
+		{SectionIDStart, m.SecStart == nil, m.writeSectionStart},
+		{SectionIDElement, len(m.SecElement) == 0, m.writeSectionElement},
+		{SectionIDDataCount, m.SecDataCount == nil, m.writeSectionDataCount},
+		{SectionIDCode, len(m.SecCode) == 0, m.writeSectionCode},
+		{SectionIDData, len(m.SecData) == 0, m.writeSectionData},
+	}
+}
+
+// Encode returns the module binary of m.
+//
+// A decoded module is written section by section in the order of Module.Sections: the sections whose
+// bytes are kept, see DecodeOptions.KeepRawSections, are copied as they are, so changes to their content
+// are not written, the other ones are encoded. Standard sections which are not in Module.Sections are
+// inserted in the order of the binary format, and custom sections beyond the decoded ones are written
+// at the end.
+//
+// A module which is not decoded, e.g. built by the builder package, is written in the order of
+// the binary format with the custom sections at the end. The integers are encoded in their shortest form,
+// so a module decoded from a canonical binary is encoded back into the same bytes.
+func (m *Module) Encode() ([]byte, error) {
+	w := &writer{}
+	w.buf = append(w.buf, params.MagicNumber...)
+	w.buf = append(w.buf, params.Version...)
+
+	pending := m.encodedSections()
+	// flush writes the pending standard sections which come before the one of id, or all of them if id is custom
+	flush := func(id SectionID) error {
+		for len(pending) > 0 && (id == SectionIDCustom || sectionRank(pending[0].id) < sectionRank(id)) {
+			s := pending[0]
+			pending = pending[1:]
+			if s.empty {
+				continue
+			}
+			if err := w.section(s.id, s.write); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	customs := 0
+	for _, info := range m.Sections {
+		if info.ID.known() && info.ID != SectionIDCustom {
+			if err := flush(info.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		raw, err := info.LoadRaw()
+		if err != nil {
+			return nil, err
+		}
+		if info.ID == SectionIDCustom {
+			customs++
+		}
+		if raw != nil {
+			w.buf = append(w.buf, raw...)
+			if len(pending) > 0 && pending[0].id == info.ID {
+				pending = pending[1:]
+			}
+			continue
+		}
+
+		switch {
+		case info.ID == SectionIDCustom:
+			if customs > len(m.SecCustoms) {
+				continue
+			}
+			if err := m.writeCustom(w, m.SecCustoms[customs-1]); err != nil {
+				return nil, err
+			}
+		case info.ID.known():
+			if len(pending) == 0 || pending[0].id != info.ID {
+				// a duplicate section, which only Recover lets through
+				continue
+			}
+			s := pending[0]
+			pending = pending[1:]
+			if err := w.section(s.id, s.write); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("write section for %d: %w", info.ID, common.ErrUnknownSection)
+		}
+	}
+
+	if err := flush(SectionIDCustom); err != nil {
+		return nil, err
+	}
+	for ; customs < len(m.SecCustoms); customs++ {
+		if err := m.writeCustom(w, m.SecCustoms[customs]); err != nil {
+			return nil, err
+		}
+	}
+	return w.buf, nil
+}
+
+// sectionRank returns the position of the standard section of id in the binary format,
+// where the data count section comes before the code section
+func sectionRank(id SectionID) int {
+	switch id {
+	case SectionIDDataCount:
+		return int(SectionIDElement) + 1
+	case SectionIDCode, SectionIDData:
+		return int(id) + 1
+	}
+	return int(id)
+}
+
+func (m *Module) writeCustom(w *writer, c *CustomSec) error {
+	return w.section(SectionIDCustom, func(w *writer) error {
+		w.name(c.Name)
+		w.buf = append(w.buf, c.Bytes...)
+		return nil
+	})
+}
+
+func (m *Module) writeSectionType(w *writer) error {
+	w.uint32(uint32(len(m.SecType)))
+	for _, ft := range m.SecType {
+		w.byte(FuncType)
+		writeValueTypes(w, ft.InputType)
+		writeValueTypes(w, ft.ReturnType)
+	}
+	return nil
+}
+
+func (m *Module) writeSectionImport(w *writer) error {
+	w.uint32(uint32(len(m.SecImport)))
+	for i, imp := range m.SecImport {
+		w.name(imp.Module)
+		w.name(imp.Name)
+		w.byte(byte(imp.Desc.Kind()))
+
+		switch desc := imp.Desc.(type) {
+		case *FuncImport:
+			w.uint32(desc.TypeIndex)
+		case *TableImport:
+			writeTableType(w, desc.Type)
+		case *MemoryImport:
+			writeLimitType(w, desc.Type)
+		case *GlobalImport:
+			writeGlobalType(w, desc.Type)
+		default:
+			return fmt.Errorf("invalid description of %v-th import: %T", i, imp.Desc)
+		}
+	}
+	return nil
+}
+
+func (m *Module) writeSectionFunction(w *writer) error {
+	w.uint32(uint32(len(m.SecFunction)))
+	for _, idx := range m.SecFunction {
+		w.uint32(idx)
+	}
+	return nil
+}
+
+func (m *Module) writeSectionTable(w *writer) error {
+	w.uint32(uint32(len(m.SecTable)))
+	for _, t := range m.SecTable {
+		writeTableType(w, t)
+	}
+	return nil
+}
+
+func (m *Module) writeSectionMemory(w *writer) error {
+	w.uint32(uint32(len(m.SecMemory)))
+	for _, l := range m.SecMemory {
+		writeLimitType(w, l)
+	}
+	return nil
+}
+
+func (m *Module) writeSectionGlobal(w *writer) error {
+	w.uint32(uint32(len(m.SecGlobal)))
+	for i, g := range m.SecGlobal {
+		writeGlobalType(w, g.Type)
+		if err := writeConstExpression(w, g.Init); err != nil {
+			return fmt.Errorf("write %v-th global: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (m *Module) writeSectionExport(w *writer) error {
+	w.uint32(uint32(len(m.SecExport)))
+	for _, exp := range m.SecExport {
+		w.name(exp.Name)
+		w.byte(byte(exp.Desc.Kind))
+		w.uint32(exp.Desc.Index)
+	}
+	return nil
+}
+
+func (m *Module) writeSectionStart(w *writer) error {
+	w.uint32(*m.SecStart)
+	return nil
+}
+
+func (m *Module) writeSectionElement(w *writer) error {
+	w.uint32(uint32(len(m.SecElement)))
+	for i, e := range m.SecElement {
+		if err := writeElementSegment(w, e); err != nil {
+			return fmt.Errorf("write %v-th element segment: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (m *Module) writeSectionDataCount(w *writer) error {
+	w.uint32(*m.SecDataCount)
+	return nil
+}
+
+func (m *Module) writeSectionCode(w *writer) error {
+	w.uint32(uint32(len(m.SecCode)))
+	for i, c := range m.SecCode {
+		if c == nil {
+			return fmt.Errorf("%v-th code segment is missing", i)
+		}
+		body, err := c.LoadBody()
+		if err != nil {
+			return fmt.Errorf("write %v-th code segment: %w", i, err)
+		}
+
+		seg := &writer{}
+		seg.uint32(uint32(len(c.Locals)))
+		for _, l := range c.Locals {
+			seg.uint32(l.Count)
+			seg.byte(l.Type.Code())
+		}
+		seg.buf = append(seg.buf, body...)
+		w.bytes(seg.buf)
+	}
+	return nil
+}
+
+func (m *Module) writeSectionData(w *writer) error {
+	w.uint32(uint32(len(m.SecData)))
+	for i, d := range m.SecData {
+		init, err := d.LoadInit()
+		if err != nil {
+			return fmt.Errorf("write %v-th data segment: %w", i, err)
+		}
+
+		switch {
+		case d.Mode == SegmentModePassive:
+			w.uint32(1)
+		case d.MemIdx == 0:
+			w.uint32(0)
+			err = writeConstExpression(w, d.Offset)
+		default:
+			w.uint32(2)
+			w.uint32(d.MemIdx)
+			err = writeConstExpression(w, d.Offset)
+		}
+		if err != nil {
+			return fmt.Errorf("write offset of %v-th data segment: %w", i, err)
+		}
+		w.bytes(init)
+	}
+	return nil
+}
+
+// writeElementSegment writes e with the flag of the shortest form, see readElementSegment
+func writeElementSegment(w *writer, e *ElementSegment) error {
+	var flag uint32
+	switch e.Mode {
+	case SegmentModePassive:
+		flag = 1
+	case SegmentModeDeclarative:
+		flag = 3
+	default:
+		if e.TableIdx != 0 || e.ElemType != ElemTypeFuncRef {
+			flag = 2
+		}
+	}
+	if e.Exprs != nil {
+		flag |= 4
+	} else if e.ElemType != ElemTypeFuncRef {
+		return fmt.Errorf("function indices of element type %v", e.ElemType)
+	}
+
+	w.uint32(flag)
+	if flag&1 == 0 {
+		if flag&2 != 0 {
+			w.uint32(e.TableIdx)
+		}
+		if err := writeConstExpression(w, e.Offset); err != nil {
+			return fmt.Errorf("write offset: %w", err)
+		}
+	}
+	if flag&3 != 0 {
+		if flag&4 != 0 {
+			w.byte(e.ElemType.Code())
+		} else {
+			// element kind of funcref
+			w.byte(0x00)
+		}
+	}
+
+	if flag&4 != 0 {
+		w.uint32(uint32(len(e.Exprs)))
+		for i, expr := range e.Exprs {
+			if err := writeConstExpression(w, expr); err != nil {
+				return fmt.Errorf("write %v-th expression: %w", i, err)
+			}
+		}
+		return nil
+	}
+
+	w.uint32(uint32(len(e.Init)))
+	for _, idx := range e.Init {
+		w.uint32(idx)
+	}
+	return nil
+}
+
+func writeValueTypes(w *writer, vts []ValueType) {
+	w.uint32(uint32(len(vts)))
+	for _, vt := range vts {
+		w.byte(vt.Code())
+	}
+}
+
+func writeTableType(w *writer, t *TableType) {
+	w.byte(t.ElemType.Code())
+	writeLimitType(w, t.Limit)
+}
+
+func writeLimitType(w *writer, l *LimitType) {
+	w.byte(l.Tag)
+	if l.Is64() {
+		w.buf = append(w.buf, common.EncodeUint64(uint64(l.Min))...)
+		if l.HasMax() {
+			w.buf = append(w.buf, common.EncodeUint64(uint64(l.Max))...)
+		}
+		return
+	}
+
+	w.uint32(l.Min)
+	if l.HasMax() {
+		w.uint32(l.Max)
+	}
+}
+
+func writeGlobalType(w *writer, gt *GlobalType) {
+	w.byte(gt.Value.Code())
+	if gt.Mutable {
+		w.byte(GlobalTypeMutable)
+	} else {
+		w.byte(GlobalTypeNotMutable)
+	}
+}
+
+func writeConstExpression(w *writer, e *ConstExpression) error {
+	if e == nil {
+		return errors.New("missing constant expression")
+	}
+	w.byte(byte(e.OpCode))
+	w.buf = append(w.buf, e.Data...)
+	w.byte(byte(operator.OpCodeEnd))
+	return nil
+}
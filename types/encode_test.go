@@ -0,0 +1,97 @@
+package types_test
+
+import (
+	"errors"
+	"github.com/LBruyne/wasm-decode/builder"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/decode"
+	"github.com/LBruyne/wasm-decode/operator"
+	"github.com/LBruyne/wasm-decode/types"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	for _, fn := range []string{exampleFile, fibFile} {
+		buf, err := ioutil.ReadFile(fn)
+		assert.Nil(t, err)
+		mod, err := decode.DecodeBytes(buf)
+		assert.Nil(t, err)
+
+		out, err := mod.Encode()
+		assert.Nil(t, err)
+		assert.Equal(t, buf, out, fn)
+	}
+}
+
+// nonCanonicalModule returns a module binary with a custom section between the type and import sections,
+// the size of the function section padded to 5 bytes and an unknown section of id 0x20 at the end
+func nonCanonicalModule(t *testing.T) []byte {
+	b := builder.New()
+	sig := b.AddType([]types.ValueType{types.ValueTypeI32}, nil)
+	b.ImportFunc("env", "log", sig)
+	f := b.AddFunction(b.AddType(nil, nil), nil, []byte{byte(operator.OpCodeEnd)})
+	b.ExportFunc("run", f)
+	bs, err := b.Bytes()
+	assert.Nil(t, err)
+
+	mod, err := decode.DecodeBytesWithOptions(bs, &decode.DecodeOptions{KeepRawSections: true})
+	assert.Nil(t, err)
+
+	out := append([]byte{}, bs[:8]...)
+	for _, s := range mod.Sections {
+		switch s.ID {
+		case types.SectionIDImport:
+			out = append(out, 0x00, 0x05, 0x04, 'n', 'o', 't', 'e')
+		case types.SectionIDFunction:
+			payload := s.Raw[1+s.SizeWidth():]
+			n := byte(len(payload))
+			out = append(out, byte(s.ID), 0x80|n, 0x80, 0x80, 0x80, 0x00)
+			out = append(out, payload...)
+			continue
+		}
+		out = append(out, s.Raw...)
+	}
+	return append(out, 0x20, 0x02, 0xab, 0xcd)
+}
+
+func TestEncodeNonCanonical(t *testing.T) {
+	bs := nonCanonicalModule(t)
+
+	mod, err := decode.DecodeBytesWithOptions(bs, &decode.DecodeOptions{KeepRawSections: true, AllowUnknownSections: true})
+	assert.Nil(t, err)
+	out, err := mod.Encode()
+	assert.Nil(t, err)
+	assert.Equal(t, bs, out)
+
+	// without the raw sections the order is kept but the integers are encoded in their shortest form
+	mod, err = decode.DecodeBytesWithOptions(bs, &decode.DecodeOptions{AllowUnknownSections: true})
+	assert.Nil(t, err)
+	out, err = mod.Encode()
+	assert.Nil(t, err)
+	assert.Len(t, out, len(bs)-4)
+	again, err := decode.DecodeBytesWithOptions(out, &decode.DecodeOptions{AllowUnknownSections: true})
+	assert.Nil(t, err)
+	var ids []types.SectionID
+	for _, s := range again.Sections {
+		ids = append(ids, s.ID)
+	}
+	assert.Equal(t, []types.SectionID{types.SectionIDType, types.SectionIDCustom, types.SectionIDImport,
+		types.SectionIDFunction, types.SectionIDExport, types.SectionIDCode, 0x20}, ids)
+
+	// a section added to a decoded module is inserted in the order of the binary format
+	start := uint32(1)
+	mod.SecStart = &start
+	out, err = mod.Encode()
+	assert.Nil(t, err)
+	again, err = decode.DecodeBytesWithOptions(out, &decode.DecodeOptions{AllowUnknownSections: true})
+	assert.Nil(t, err)
+	assert.Equal(t, types.SectionIDStart, again.Sections[5].ID)
+	assert.Equal(t, start, *again.SecStart)
+
+	// the unknown sections need their bytes
+	mod.Sections[len(mod.Sections)-1].Raw = nil
+	_, err = mod.Encode()
+	assert.True(t, errors.Is(err, common.ErrUnknownSection))
+}
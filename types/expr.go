@@ -1,11 +1,12 @@
 package types
 
 import (
-	"bytes"
+	"encoding/binary"
 	"fmt"
 	"github.com/LBruyne/wasm-decode/common"
 	"github.com/LBruyne/wasm-decode/operator"
-	"io"
+	"math"
+	"strconv"
 )
 
 // ConstExpression const expression defines the OpCode must be xx.const instruction and data is the immediate
@@ -16,56 +17,277 @@ type ConstExpression struct {
 
 type OffsetExpression = ConstExpression
 
-func readOffsetExpression(r io.Reader) (*OffsetExpression, error) {
+func readOffsetExpression(r *reader) (*OffsetExpression, error) {
 	return readConstExpression(r)
 }
 
 type InitExpression = ConstExpression
 
-func readInitExpression(r io.Reader) (*InitExpression, error) {
+func readInitExpression(r *reader) (*InitExpression, error) {
 	return readConstExpression(r)
 }
 
-func readConstExpression(r io.Reader) (*ConstExpression, error) {
-	b := make([]byte, 1)
-	_, err := io.ReadFull(r, b)
+func readConstExpression(r *reader) (*ConstExpression, error) {
+	b, err := r.ReadByte()
 	if err != nil {
 		return nil, fmt.Errorf("read OpCode: %w", err)
 	}
 
-	buf := new(bytes.Buffer)
-	teeR := io.TeeReader(r, buf)
+	// v128.const is the only constant instruction after a prefix, its sub OpCode is read by readV128Const
+	OpCode := operator.OpCode(b)
+	var sub uint32
+	if OpCode == operator.OpCodePrefixSIMD {
+		sub = operator.OpCodeSIMDV128Const
+	}
+	info, ok := operator.Lookup(OpCode, sub)
+	if !ok || !info.Const {
+		return nil, fmt.Errorf("%v is not allowed in constant expression", OpCode)
+	}
+
+	c := r.startCapture(16)
+	if info.Feature != 0 {
+		err = r.require(info.Feature, info.Name)
+	}
+	if err == nil {
+		if OpCode == operator.OpCodePrefixSIMD {
+			err = readV128Const(r)
+		} else {
+			err = readConstImmediates(r, info.Imms)
+		}
+	}
+
+	data := r.stopCapture(c)
+	if err != nil {
+		return nil, fmt.Errorf("read value: %w", err)
+	}
+
+	if b, err = r.ReadByte(); err != nil {
+		return nil, fmt.Errorf("look for end OpCode: %w", err)
+	}
+
+	if b != byte(operator.OpCodeEnd) {
+		return nil, fmt.Errorf("constant expression has not terminated")
+	}
+
+	ret := r.slabs.newConstExpression()
+	*ret = ConstExpression{
+		OpCode: OpCode,
+		Data:   data,
+	}
+	return ret, nil
+}
+
+// readConstImmediates reads over the immediates of a constant instruction
+func readConstImmediates(r *reader, imms []operator.Immediate) (err error) {
+	for _, imm := range imms {
+		switch imm {
+		case operator.ImmI32:
+			_, err = r.readInt32()
+		case operator.ImmI64:
+			_, err = r.readInt64()
+		case operator.ImmF32:
+			err = r.skip(4)
+		case operator.ImmF64:
+			err = r.skip(8)
+		case operator.ImmGlobal, operator.ImmFunc:
+			_, err = r.readUint32()
+		case operator.ImmRefType:
+			_, err = r.ReadByte()
+		default:
+			err = fmt.Errorf("invalid immediate of constant instruction: %v", imm)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readV128Const read the sub OpCode and the immediate of v128.const
+func readV128Const(r *reader) error {
+	op, err := r.readUint32()
+	if err != nil {
+		return err
+	}
+	if op != operator.OpCodeSIMDV128Const {
+		return fmt.Errorf("invalid SIMD OpCode in constant expression: %#x", op)
+	}
+
+	_, err = r.readBytes(16)
+	return err
+}
+
+// GlobalRef is the value of global.get, which is the one of the global at Index
+type GlobalRef struct {
+	Index uint32
+}
+
+// FuncRef is the value of ref.func, a reference to the function at Index
+type FuncRef struct {
+	Index uint32
+}
+
+// NullRef is the value of ref.null, a null reference of Type
+type NullRef struct {
+	Type ValueType
+}
+
+// V128 is the value of v128.const in little endian
+type V128 [16]byte
 
-	OpCode := operator.OpCode(b[0])
-	switch OpCode {
+// NewI32Const returns the expression i32.const v
+func NewI32Const(v int32) *ConstExpression {
+	return &ConstExpression{OpCode: operator.OpCodeI32Const, Data: common.EncodeInt32(v)}
+}
+
+// NewI64Const returns the expression i64.const v
+func NewI64Const(v int64) *ConstExpression {
+	return &ConstExpression{OpCode: operator.OpCodeI64Const, Data: common.EncodeInt64(v)}
+}
+
+// NewF32Const returns the expression f32.const v
+func NewF32Const(v float32) *ConstExpression {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, math.Float32bits(v))
+	return &ConstExpression{OpCode: operator.OpCodeF32Const, Data: data}
+}
+
+// NewF64Const returns the expression f64.const v
+func NewF64Const(v float64) *ConstExpression {
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint64(data, math.Float64bits(v))
+	return &ConstExpression{OpCode: operator.OpCodeF64Const, Data: data}
+}
+
+// NewV128Const returns the expression v128.const v
+func NewV128Const(v V128) *ConstExpression {
+	data := append(common.EncodeUint32(operator.OpCodeSIMDV128Const), v[:]...)
+	return &ConstExpression{OpCode: operator.OpCodePrefixSIMD, Data: data}
+}
+
+// NewGlobalGet returns the expression global.get idx
+func NewGlobalGet(idx uint32) *ConstExpression {
+	return &ConstExpression{OpCode: operator.OpCodeGlobalGet, Data: common.EncodeUint32(idx)}
+}
+
+// NewRefNull returns the expression ref.null of the reference type t
+func NewRefNull(t ValueType) *ConstExpression {
+	return &ConstExpression{OpCode: operator.OpCodeRefNull, Data: []byte{t.Code()}}
+}
+
+// NewRefFunc returns the expression ref.func idx
+func NewRefFunc(idx uint32) *ConstExpression {
+	return &ConstExpression{OpCode: operator.OpCodeRefFunc, Data: common.EncodeUint32(idx)}
+}
+
+// Value returns the value of the expression, which is one of int32, int64, float32, float64,
+// V128, GlobalRef, FuncRef and NullRef
+func (e *ConstExpression) Value() (interface{}, error) {
+	switch e.OpCode {
 	case operator.OpCodeI32Const:
-		_, _, err = common.DecodeInt32(teeR)
+		v, _, err := common.DecodeInt32Bytes(e.Data)
+		return v, err
 	case operator.OpCodeI64Const:
-		_, _, err = common.DecodeInt64(teeR)
+		v, _, err := common.DecodeInt64Bytes(e.Data)
+		return v, err
 	case operator.OpCodeF32Const:
-		_, err = ReadFloat32(teeR)
+		if len(e.Data) != 4 {
+			return nil, fmt.Errorf("f32.const of %d bytes", len(e.Data))
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(e.Data)), nil
 	case operator.OpCodeF64Const:
-		_, err = ReadFloat64(teeR)
+		if len(e.Data) != 8 {
+			return nil, fmt.Errorf("f64.const of %d bytes", len(e.Data))
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(e.Data)), nil
+	case operator.OpCodePrefixSIMD:
+		_, n, err := common.DecodeUint32Bytes(e.Data)
+		if err != nil {
+			return nil, err
+		}
+		var v V128
+		if len(e.Data)-n != len(v) {
+			return nil, fmt.Errorf("v128.const of %d bytes", len(e.Data)-n)
+		}
+		copy(v[:], e.Data[n:])
+		return v, nil
 	case operator.OpCodeGlobalGet:
-		_, _, err = common.DecodeUint32(teeR)
-	default:
-		return nil, fmt.Errorf("invalid byte for opt code: %#x", b[0])
+		idx, _, err := common.DecodeUint32Bytes(e.Data)
+		return GlobalRef{Index: idx}, err
+	case operator.OpCodeRefFunc:
+		idx, _, err := common.DecodeUint32Bytes(e.Data)
+		return FuncRef{Index: idx}, err
+	case operator.OpCodeRefNull:
+		if len(e.Data) != 1 {
+			return nil, fmt.Errorf("ref.null of %d bytes", len(e.Data))
+		}
+		return NullRef{Type: ValueType(e.Data[0])}, nil
 	}
+	return nil, fmt.Errorf("invalid OpCode of constant expression: %#x", byte(e.OpCode))
+}
 
+// Type returns the type of the value of the expression, or 0 for global.get whose type is the one
+// of the global, see Module.ConstExpressionType
+func (e *ConstExpression) Type() ValueType {
+	switch e.OpCode {
+	case operator.OpCodeI32Const:
+		return ValueTypeI32
+	case operator.OpCodeI64Const:
+		return ValueTypeI64
+	case operator.OpCodeF32Const:
+		return ValueTypeF32
+	case operator.OpCodeF64Const:
+		return ValueTypeF64
+	case operator.OpCodePrefixSIMD:
+		return ValueTypeV128
+	case operator.OpCodeRefFunc:
+		return ValueTypeFuncRef
+	case operator.OpCodeRefNull:
+		if len(e.Data) == 1 {
+			return ValueType(e.Data[0])
+		}
+	}
+	return 0
+}
+
+func (e *ConstExpression) String() string {
+	v, err := e.Value()
 	if err != nil {
-		return nil, fmt.Errorf("read value: %w", err)
+		return fmt.Sprintf("invalid(%v)", err)
 	}
 
-	if _, err := io.ReadFull(r, b); err != nil {
-		return nil, fmt.Errorf("look for end OpCode: %w", err)
+	switch v := v.(type) {
+	case float32:
+		return "f32.const " + strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return "f64.const " + strconv.FormatFloat(v, 'g', -1, 64)
+	case V128:
+		return fmt.Sprintf("v128.const i32x4 %#x %#x %#x %#x", binary.LittleEndian.Uint32(v[0:]),
+			binary.LittleEndian.Uint32(v[4:]), binary.LittleEndian.Uint32(v[8:]), binary.LittleEndian.Uint32(v[12:]))
+	case GlobalRef:
+		return fmt.Sprintf("global.get %d", v.Index)
+	case FuncRef:
+		return fmt.Sprintf("ref.func %d", v.Index)
+	case NullRef:
+		return "ref.null " + v.Type.HeapType().String()
+	default:
+		return fmt.Sprintf("%v.const %d", e.Type(), v)
 	}
+}
 
-	if b[0] != byte(operator.OpCodeEnd) {
-		return nil, fmt.Errorf("constant expression has not terminated")
+// ConstExpressionType returns the type of the value of e, resolving the global of global.get
+func (m *Module) ConstExpressionType(e *ConstExpression) (ValueType, error) {
+	if e.OpCode != operator.OpCodeGlobalGet {
+		return e.Type(), nil
 	}
 
-	return &ConstExpression{
-		OpCode: OpCode,
-		Data:   buf.Bytes(),
-	}, nil
+	idx, _, err := common.DecodeUint32Bytes(e.Data)
+	if err != nil {
+		return 0, err
+	}
+	gt, err := m.globalType(idx)
+	if err != nil {
+		return 0, err
+	}
+	return gt.Value, nil
 }
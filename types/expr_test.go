@@ -0,0 +1,73 @@
+package types_test
+
+import (
+	"errors"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/decode"
+	"github.com/LBruyne/wasm-decode/operator"
+	"github.com/LBruyne/wasm-decode/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConstExpression(t *testing.T) {
+	v128 := types.V128{1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0, 4}
+	cases := []struct {
+		expr  *types.ConstExpression
+		value interface{}
+		typ   types.ValueType
+		str   string
+	}{
+		{types.NewI32Const(-42), int32(-42), types.ValueTypeI32, "i32.const -42"},
+		{types.NewI64Const(1 << 40), int64(1 << 40), types.ValueTypeI64, "i64.const 1099511627776"},
+		{types.NewF32Const(1.5), float32(1.5), types.ValueTypeF32, "f32.const 1.5"},
+		{types.NewF64Const(-0.25), float64(-0.25), types.ValueTypeF64, "f64.const -0.25"},
+		{types.NewV128Const(v128), v128, types.ValueTypeV128, "v128.const i32x4 0x1 0x2 0x3 0x4"},
+		{types.NewGlobalGet(3), types.GlobalRef{Index: 3}, 0, "global.get 3"},
+		{types.NewRefFunc(7), types.FuncRef{Index: 7}, types.ValueTypeFuncRef, "ref.func 7"},
+		{types.NewRefNull(types.ValueTypeExternRef), types.NullRef{Type: types.ValueTypeExternRef}, types.ValueTypeExternRef, "ref.null extern"},
+	}
+
+	for _, c := range cases {
+		v, err := c.expr.Value()
+		assert.Nil(t, err)
+		assert.Equal(t, c.value, v)
+		assert.Equal(t, c.typ, c.expr.Type())
+		assert.Equal(t, c.str, c.expr.String())
+	}
+
+	_, err := (&types.ConstExpression{OpCode: operator.OpCodeF32Const, Data: []byte{0}}).Value()
+	assert.Error(t, err)
+
+	// the decoded expressions are the ones built
+	mod, err := decode.DecodeFile(exampleFile)
+	assert.Nil(t, err)
+	for _, g := range mod.SecGlobal {
+		v, err := g.Init.Value()
+		assert.Nil(t, err)
+
+		var built *types.ConstExpression
+		switch v := v.(type) {
+		case int32:
+			built = types.NewI32Const(v)
+		case int64:
+			built = types.NewI64Const(v)
+		case types.GlobalRef:
+			built = types.NewGlobalGet(v.Index)
+		}
+		assert.Equal(t, g.Init, built)
+
+		typ, err := mod.ConstExpressionType(g.Init)
+		assert.Nil(t, err)
+		assert.Equal(t, g.Type.Value, typ)
+	}
+
+	imported := &types.Module{SecImport: []*types.ImportSegment{
+		{Module: "env", Name: "g", Desc: &types.GlobalImport{Type: &types.GlobalType{Value: types.ValueTypeF64}}},
+	}}
+	typ, err := imported.ConstExpressionType(types.NewGlobalGet(0))
+	assert.Nil(t, err)
+	assert.Equal(t, types.ValueTypeF64, typ)
+	_, err = imported.ConstExpressionType(types.NewGlobalGet(1))
+	assert.True(t, errors.Is(err, common.ErrIndexOutOfRange))
+}
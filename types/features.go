@@ -0,0 +1,285 @@
+package types
+
+import (
+	"fmt"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/feature"
+	"github.com/LBruyne/wasm-decode/operator"
+)
+
+// FeatureUsage tells where a module requires a feature for the first time
+type FeatureUsage struct {
+	Feature feature.Feature
+	Section SectionID
+	Index   uint32 // index of the item in its section, or in the function index space for code
+	Offset  int64  // offset of the instruction in the module binary, -1 if not in code
+	Reason  string // names in it are escaped by SafeName
+}
+
+func (u *FeatureUsage) String() string {
+	if u.Offset >= 0 {
+		return fmt.Sprintf("%s: func[%d] at %#x: %s", u.Feature, u.Index, u.Offset, u.Reason)
+	}
+	return fmt.Sprintf("%s: section %d, item %d: %s", u.Feature, u.Section, u.Index, u.Reason)
+}
+
+// FeatureReport lists the features used by a module
+type FeatureReport struct {
+	Features feature.Set
+	// Usages are the first usage of each feature, in the order of the module binary for the standard sections,
+	// followed by the features only declared by target_features custom sections, wherever these sections are
+	Usages []*FeatureUsage
+}
+
+func (rp *FeatureReport) add(u *FeatureUsage) {
+	if rp.Features.Has(u.Feature) {
+		return
+	}
+	rp.Features = rp.Features.With(u.Feature)
+	rp.Usages = append(rp.Usages, u)
+}
+
+func (rp *FeatureReport) addItem(f feature.Feature, sec SectionID, idx uint32, reason string) {
+	rp.add(&FeatureUsage{Feature: f, Section: sec, Index: idx, Offset: -1, Reason: reason})
+}
+
+// targetFeatures maps the names used by target_features custom section to features
+var targetFeatures = map[string]feature.Feature{
+	"sign-ext":            feature.SignExtension,
+	"nontrapping-fptoint": feature.NonTrappingFloatToInt,
+	"mutable-globals":     feature.MutableGlobals,
+	"multivalue":          feature.MultiValue,
+	"bulk-memory":         feature.BulkMemory,
+	"reference-types":     feature.ReferenceTypes,
+	"simd128":             feature.SIMD,
+	"atomics":             feature.Threads,
+	"memory64":            feature.Memory64,
+	"multimemory":         feature.MultiMemory,
+	"tail-call":           feature.TailCall,
+	"extended-const":      feature.ExtendedConst,
+	"exception-handling":  feature.ExceptionHandling,
+}
+
+// DetectFeatures reports the post-MVP features the module uses, by looking into its sections
+// and code bodies. Bodies of a lazily decoded module are loaded.
+func (m *Module) DetectFeatures() (*FeatureReport, error) {
+	rp := &FeatureReport{}
+
+	for i, ft := range m.SecType {
+		if len(ft.ReturnType) > 1 {
+			rp.addItem(feature.MultiValue, SectionIDType, uint32(i), fmt.Sprintf("%d results", len(ft.ReturnType)))
+		}
+		detectValueTypes(rp, SectionIDType, uint32(i), ft.InputType)
+		detectValueTypes(rp, SectionIDType, uint32(i), ft.ReturnType)
+	}
+
+	for i, imp := range m.SecImport {
+		switch desc := imp.Desc.(type) {
+		case *TableImport:
+			detectTableType(rp, SectionIDImport, uint32(i), desc.Type)
+		case *MemoryImport:
+			detectLimitType(rp, SectionIDImport, uint32(i), desc.Type)
+		case *GlobalImport:
+			detectValueTypes(rp, SectionIDImport, uint32(i), []ValueType{desc.Type.Value})
+			if desc.Type.Mutable {
+				rp.addItem(feature.MutableGlobals, SectionIDImport, uint32(i),
+					fmt.Sprintf("import of mutable global %s.%s", SafeName(imp.Module), SafeName(imp.Name)))
+			}
+		}
+	}
+
+	if n := m.ImportedCount(ExternalKindTable) + uint32(len(m.SecTable)); n > 1 {
+		rp.addItem(feature.ReferenceTypes, SectionIDTable, 0, fmt.Sprintf("%d tables", n))
+	}
+	for i, t := range m.SecTable {
+		detectTableType(rp, SectionIDTable, uint32(i), t)
+	}
+
+	if n := m.ImportedCount(ExternalKindMemory) + uint32(len(m.SecMemory)); n > 1 {
+		rp.addItem(feature.MultiMemory, SectionIDMemory, 0, fmt.Sprintf("%d memories", n))
+	}
+	for i, l := range m.SecMemory {
+		detectLimitType(rp, SectionIDMemory, uint32(i), l)
+	}
+
+	for i, g := range m.SecGlobal {
+		detectValueTypes(rp, SectionIDGlobal, uint32(i), []ValueType{g.Type.Value})
+		detectConstExpression(rp, SectionIDGlobal, uint32(i), g.Init)
+	}
+
+	for i, exp := range m.SecExport {
+		if exp.Desc.Kind != ExternalKindGlobal {
+			continue
+		}
+		if g, err := m.Global(exp.Desc.Index); err == nil && g.Type.Mutable {
+			rp.addItem(feature.MutableGlobals, SectionIDExport, uint32(i),
+				fmt.Sprintf("export of mutable global %s", SafeName(exp.Name)))
+		}
+	}
+
+	for i, elem := range m.SecElement {
+		if elem.Mode != SegmentModeActive || elem.TableIdx != 0 {
+			rp.addItem(feature.BulkMemory, SectionIDElement, uint32(i), "element segment of bulk memory form")
+		}
+		if elem.Mode == SegmentModeDeclarative || elem.Exprs != nil || elem.ElemType != ElemTypeFuncRef {
+			rp.addItem(feature.ReferenceTypes, SectionIDElement, uint32(i), "element segment of reference types form")
+		}
+		for _, expr := range elem.Exprs {
+			detectConstExpression(rp, SectionIDElement, uint32(i), expr)
+		}
+	}
+
+	if m.SecDataCount != nil {
+		rp.addItem(feature.BulkMemory, SectionIDDataCount, 0, "data count section")
+	}
+
+	if err := m.detectCode(rp); err != nil {
+		return nil, err
+	}
+
+	for i, data := range m.SecData {
+		if data.Mode == SegmentModePassive {
+			rp.addItem(feature.BulkMemory, SectionIDData, uint32(i), "passive data segment")
+		}
+		if data.MemIdx != 0 {
+			rp.addItem(feature.MultiMemory, SectionIDData, uint32(i), "data segment of non-zero memory index")
+		}
+	}
+
+	for i, c := range m.SecCustoms {
+		if c.Name != "target_features" {
+			continue
+		}
+		if err := detectTargetFeatures(rp, uint32(i), c); err != nil {
+			return nil, fmt.Errorf("read target_features section: %w", err)
+		}
+	}
+
+	return rp, nil
+}
+
+func detectValueTypes(rp *FeatureReport, sec SectionID, idx uint32, vts []ValueType) {
+	for _, vt := range vts {
+		switch vt {
+		case ValueTypeV128:
+			rp.addItem(feature.SIMD, sec, idx, "value type v128")
+		case ValueTypeFuncRef, ValueTypeExternRef:
+			rp.addItem(feature.ReferenceTypes, sec, idx, "value type "+vt.String())
+		}
+	}
+}
+
+func detectTableType(rp *FeatureReport, sec SectionID, idx uint32, t *TableType) {
+	if t.ElemType == ElemTypeExternRef {
+		rp.addItem(feature.ReferenceTypes, sec, idx, "table of externref")
+	}
+}
+
+func detectLimitType(rp *FeatureReport, sec SectionID, idx uint32, l *LimitType) {
+	if l.Shared() {
+		rp.addItem(feature.Threads, sec, idx, "shared memory")
+	}
+	if l.Is64() {
+		rp.addItem(feature.Memory64, sec, idx, "64-bit memory")
+	}
+}
+
+func detectConstExpression(rp *FeatureReport, sec SectionID, idx uint32, expr *ConstExpression) {
+	var sub uint32
+	if expr.OpCode == operator.OpCodePrefixSIMD {
+		sub, _, _ = common.DecodeUint32Bytes(expr.Data)
+	}
+	if info, ok := operator.Lookup(expr.OpCode, sub); ok && info.Feature != 0 {
+		rp.addItem(info.Feature, sec, idx, "constant expression of "+info.Name)
+	}
+}
+
+// detectCode looks into locals and instructions of each code segment
+func (m *Module) detectCode(rp *FeatureReport) error {
+	ifc := m.importedFuncCount()
+	for i, c := range m.SecCode {
+		if c == nil {
+			// skipped by DecodeOptions.Recover
+			continue
+		}
+		funcIdx := ifc + uint32(i)
+		for _, l := range c.Locals {
+			detectValueTypes(rp, SectionIDCode, funcIdx, []ValueType{l.Type})
+		}
+
+		body, err := c.LoadBody()
+		if err != nil {
+			return fmt.Errorf("load body of func[%d]: %w", funcIdx, err)
+		}
+
+		for off := 0; off < len(body); {
+			ins, n, err := operator.ReadInstruction(body[off:])
+			if err != nil {
+				return fmt.Errorf("read instruction of func[%d] at %#x: %w", funcIdx, c.BodyOffset+int64(off), err)
+			}
+
+			if f, ok := instructionFeature(ins); ok {
+				rp.add(&FeatureUsage{
+					Feature: f,
+					Section: SectionIDCode,
+					Index:   funcIdx,
+					Offset:  c.BodyOffset + int64(off),
+					Reason:  describeInstruction(ins),
+				})
+			}
+			off += n
+		}
+	}
+	return nil
+}
+
+// instructionFeature returns the feature an instruction belongs to, if it is not in WASM 1.0
+func instructionFeature(ins *operator.Instruction) (feature.Feature, bool) {
+	if info, ok := ins.Info(); ok && info.Feature != 0 {
+		return info.Feature, true
+	}
+
+	switch ins.OpCode {
+	case operator.OpCodeBlock, operator.OpCodeLoop, operator.OpCodeIf:
+		if _, ok := operator.BlockTypeIndex(ins.Imm); ok {
+			return feature.MultiValue, true
+		}
+	case operator.OpCodeCallIndirect:
+		// the table index is a zero byte in WASM 1.0
+		if len(ins.Imm) > 0 && ins.Imm[len(ins.Imm)-1] != 0 {
+			return feature.ReferenceTypes, true
+		}
+	}
+	return 0, false
+}
+
+func describeInstruction(ins *operator.Instruction) string {
+	switch ins.OpCode {
+	case operator.OpCodeBlock, operator.OpCodeLoop, operator.OpCodeIf:
+		if _, ok := operator.BlockTypeIndex(ins.Imm); ok {
+			return fmt.Sprintf("block type of type index in %s", ins.Name())
+		}
+	case operator.OpCodeCallIndirect:
+		return fmt.Sprintf("table index in %s", ins.Name())
+	}
+	return fmt.Sprintf("instruction %s", ins.Name())
+}
+
+// detectTargetFeatures reports the features declared with '+' in target_features section
+func detectTargetFeatures(rp *FeatureReport, idx uint32, c *CustomSec) error {
+	ts, ok := c.Value.(*TargetFeaturesSection)
+	if !ok {
+		v, err := decodeTargetFeaturesSection(c.Bytes)
+		if err != nil {
+			return err
+		}
+		ts = v.(*TargetFeaturesSection)
+	}
+
+	for _, tf := range ts.Features {
+		if f, ok := targetFeatures[tf.Name]; ok && tf.Prefix == '+' {
+			rp.addItem(f, SectionIDCustom, idx, fmt.Sprintf("declared by target_features as %q", tf.Name))
+		}
+	}
+	return nil
+}
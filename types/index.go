@@ -0,0 +1,231 @@
+package types
+
+import (
+	"fmt"
+	"github.com/LBruyne/wasm-decode/common"
+)
+
+// Function is a function of the index space of functions, where the imported functions come first
+type Function struct {
+	Index     uint32
+	TypeIndex uint32
+	Type      *FunctionType // nil if TypeIndex is out of range
+
+	Import *ImportSegment // nil if the function is defined by the module
+	Code   *CodeSegment   // body of a defined function, nil if it is imported or missing
+}
+
+// Imported reports whether the function is imported
+func (f *Function) Imported() bool {
+	return f.Import != nil
+}
+
+// Table is a table of the index space of tables, where the imported tables come first
+type Table struct {
+	Index  uint32
+	Type   *TableType
+	Import *ImportSegment // nil if the table is defined by the module
+}
+
+// Imported reports whether the table is imported
+func (t *Table) Imported() bool {
+	return t.Import != nil
+}
+
+// Memory is a memory of the index space of memories, where the imported memories come first
+type Memory struct {
+	Index  uint32
+	Type   *MemoryType
+	Import *ImportSegment // nil if the memory is defined by the module
+}
+
+// Imported reports whether the memory is imported
+func (mem *Memory) Imported() bool {
+	return mem.Import != nil
+}
+
+// Global is a global of the index space of globals, where the imported globals come first
+type Global struct {
+	Index  uint32
+	Type   *GlobalType
+	Init   *InitExpression // initial value of a defined global, nil if it is imported
+	Import *ImportSegment  // nil if the global is defined by the module
+}
+
+// Imported reports whether the global is imported
+func (g *Global) Imported() bool {
+	return g.Import != nil
+}
+
+// ImportedCount returns the number of imported components of kind, which come first in its index space
+func (m *Module) ImportedCount(kind ExternalKind) (n uint32) {
+	for _, imp := range m.SecImport {
+		if imp.Desc.Kind() == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// importAt returns the import of kind at idx of its index space, or nil and the number of imports of kind
+func (m *Module) importAt(kind ExternalKind, idx uint32) (*ImportSegment, uint32) {
+	var n uint32
+	for _, imp := range m.SecImport {
+		if imp.Desc.Kind() != kind {
+			continue
+		}
+		if n == idx {
+			return imp, n
+		}
+		n++
+	}
+	return nil, n
+}
+
+// typeAt returns the function type at idx of the type section, or nil
+func (m *Module) typeAt(idx uint32) *FunctionType {
+	if int64(idx) < int64(len(m.SecType)) {
+		return m.SecType[idx]
+	}
+	return nil
+}
+
+// Functions returns the index space of functions
+func (m *Module) Functions() []*Function {
+	ret := make([]*Function, 0, int(m.importedFuncCount())+len(m.SecFunction))
+	for _, imp := range m.SecImport {
+		if imp.Desc.Kind() == ExternalKindFunc {
+			ret = append(ret, m.importedFunction(uint32(len(ret)), imp))
+		}
+	}
+	for i := range m.SecFunction {
+		ret = append(ret, m.definedFunction(uint32(len(ret)), i))
+	}
+	return ret
+}
+
+// Function returns the function at idx of the index space of functions
+func (m *Module) Function(idx uint32) (*Function, error) {
+	imp, n := m.importAt(ExternalKindFunc, idx)
+	if imp != nil {
+		return m.importedFunction(idx, imp), nil
+	}
+	if i := int64(idx - n); i < int64(len(m.SecFunction)) {
+		return m.definedFunction(idx, int(i)), nil
+	}
+	return nil, fmt.Errorf("%w: function %d of %d", common.ErrIndexOutOfRange, idx, int(n)+len(m.SecFunction))
+}
+
+func (m *Module) importedFunction(idx uint32, imp *ImportSegment) *Function {
+	ti := imp.Desc.(*FuncImport).TypeIndex
+	return &Function{
+		Index:     idx,
+		TypeIndex: ti,
+		Type:      m.typeAt(ti),
+		Import:    imp,
+	}
+}
+
+// definedFunction returns the function at idx of the index space, which is the i-th defined one
+func (m *Module) definedFunction(idx uint32, i int) *Function {
+	f := &Function{
+		Index:     idx,
+		TypeIndex: m.SecFunction[i],
+		Type:      m.typeAt(m.SecFunction[i]),
+	}
+	if i < len(m.SecCode) {
+		f.Code = m.SecCode[i]
+	}
+	return f
+}
+
+// Tables returns the index space of tables
+func (m *Module) Tables() []*Table {
+	ret := make([]*Table, 0, int(m.ImportedCount(ExternalKindTable))+len(m.SecTable))
+	for _, imp := range m.SecImport {
+		if desc, ok := imp.Desc.(*TableImport); ok {
+			ret = append(ret, &Table{Index: uint32(len(ret)), Type: desc.Type, Import: imp})
+		}
+	}
+	for _, t := range m.SecTable {
+		ret = append(ret, &Table{Index: uint32(len(ret)), Type: t})
+	}
+	return ret
+}
+
+// Table returns the table at idx of the index space of tables
+func (m *Module) Table(idx uint32) (*Table, error) {
+	imp, n := m.importAt(ExternalKindTable, idx)
+	if imp != nil {
+		return &Table{Index: idx, Type: imp.Desc.(*TableImport).Type, Import: imp}, nil
+	}
+	if i := int64(idx - n); i < int64(len(m.SecTable)) {
+		return &Table{Index: idx, Type: m.SecTable[i]}, nil
+	}
+	return nil, fmt.Errorf("%w: table %d of %d", common.ErrIndexOutOfRange, idx, int(n)+len(m.SecTable))
+}
+
+// Memories returns the index space of memories
+func (m *Module) Memories() []*Memory {
+	ret := make([]*Memory, 0, int(m.ImportedCount(ExternalKindMemory))+len(m.SecMemory))
+	for _, imp := range m.SecImport {
+		if desc, ok := imp.Desc.(*MemoryImport); ok {
+			ret = append(ret, &Memory{Index: uint32(len(ret)), Type: desc.Type, Import: imp})
+		}
+	}
+	for _, mem := range m.SecMemory {
+		ret = append(ret, &Memory{Index: uint32(len(ret)), Type: mem})
+	}
+	return ret
+}
+
+// Memory returns the memory at idx of the index space of memories
+func (m *Module) Memory(idx uint32) (*Memory, error) {
+	imp, n := m.importAt(ExternalKindMemory, idx)
+	if imp != nil {
+		return &Memory{Index: idx, Type: imp.Desc.(*MemoryImport).Type, Import: imp}, nil
+	}
+	if i := int64(idx - n); i < int64(len(m.SecMemory)) {
+		return &Memory{Index: idx, Type: m.SecMemory[i]}, nil
+	}
+	return nil, fmt.Errorf("%w: memory %d of %d", common.ErrIndexOutOfRange, idx, int(n)+len(m.SecMemory))
+}
+
+// Globals returns the index space of globals
+func (m *Module) Globals() []*Global {
+	ret := make([]*Global, 0, int(m.ImportedCount(ExternalKindGlobal))+len(m.SecGlobal))
+	for _, imp := range m.SecImport {
+		if desc, ok := imp.Desc.(*GlobalImport); ok {
+			ret = append(ret, &Global{Index: uint32(len(ret)), Type: desc.Type, Import: imp})
+		}
+	}
+	for _, g := range m.SecGlobal {
+		ret = append(ret, &Global{Index: uint32(len(ret)), Type: g.Type, Init: g.Init})
+	}
+	return ret
+}
+
+// Global returns the global at idx of the index space of globals
+func (m *Module) Global(idx uint32) (*Global, error) {
+	imp, n := m.importAt(ExternalKindGlobal, idx)
+	if imp != nil {
+		return &Global{Index: idx, Type: imp.Desc.(*GlobalImport).Type, Import: imp}, nil
+	}
+	if i := int64(idx - n); i < int64(len(m.SecGlobal)) {
+		g := m.SecGlobal[i]
+		return &Global{Index: idx, Type: g.Type, Init: g.Init}, nil
+	}
+	return nil, fmt.Errorf("%w: global %d of %d", common.ErrIndexOutOfRange, idx, int(n)+len(m.SecGlobal))
+}
+
+// globalType returns the type of the global of idx, without making a Global
+func (m *Module) globalType(idx uint32) (*GlobalType, error) {
+	imp, n := m.importAt(ExternalKindGlobal, idx)
+	if imp != nil {
+		return imp.Desc.(*GlobalImport).Type, nil
+	}
+	if i := int64(idx - n); i < int64(len(m.SecGlobal)) {
+		return m.SecGlobal[i].Type, nil
+	}
+	return nil, fmt.Errorf("%w: global %d of %d", common.ErrIndexOutOfRange, idx, int(n)+len(m.SecGlobal))
+}
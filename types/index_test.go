@@ -0,0 +1,85 @@
+package types_test
+
+import (
+	"errors"
+	"github.com/LBruyne/wasm-decode/builder"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/decode"
+	"github.com/LBruyne/wasm-decode/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIndexSpaces(t *testing.T) {
+	mod, err := decode.DecodeFile(exampleFile)
+	assert.Nil(t, err)
+
+	ifc := int(mod.ImportedCount(types.ExternalKindFunc))
+	funcs := mod.Functions()
+	assert.Len(t, funcs, ifc+len(mod.SecFunction))
+	for i, f := range funcs {
+		assert.Equal(t, uint32(i), f.Index)
+		assert.Equal(t, mod.SecType[f.TypeIndex], f.Type)
+		assert.Equal(t, i < ifc, f.Imported())
+		if !f.Imported() {
+			assert.Equal(t, mod.SecCode[i-ifc], f.Code)
+		}
+
+		g, err := mod.Function(uint32(i))
+		assert.Nil(t, err)
+		assert.Equal(t, f, g)
+	}
+	_, err = mod.Function(uint32(len(funcs)))
+	assert.True(t, errors.Is(err, common.ErrIndexOutOfRange))
+
+	globals := mod.Globals()
+	assert.Len(t, globals, int(mod.ImportedCount(types.ExternalKindGlobal))+len(mod.SecGlobal))
+	for i, g := range globals {
+		assert.Equal(t, uint32(i), g.Index)
+		assert.Equal(t, g.Imported(), g.Init == nil)
+		h, err := mod.Global(uint32(i))
+		assert.Nil(t, err)
+		assert.Equal(t, g, h)
+	}
+	_, err = mod.Global(uint32(len(globals)))
+	assert.True(t, errors.Is(err, common.ErrIndexOutOfRange))
+
+	mems := mod.Memories()
+	assert.Len(t, mems, int(mod.ImportedCount(types.ExternalKindMemory))+len(mod.SecMemory))
+	for i, m := range mems {
+		n, err := mod.Memory(uint32(i))
+		assert.Nil(t, err)
+		assert.Equal(t, m, n)
+	}
+	_, err = mod.Memory(uint32(len(mems)))
+	assert.True(t, errors.Is(err, common.ErrIndexOutOfRange))
+
+	tables := mod.Tables()
+	assert.Len(t, tables, int(mod.ImportedCount(types.ExternalKindTable))+len(mod.SecTable))
+	for i, tb := range tables {
+		u, err := mod.Table(uint32(i))
+		assert.Nil(t, err)
+		assert.Equal(t, tb, u)
+	}
+	_, err = mod.Table(uint32(len(tables)))
+	assert.True(t, errors.Is(err, common.ErrIndexOutOfRange))
+
+	// the imported ones come first
+	b := builder.New()
+	b.ImportGlobal("env", "g", types.ValueTypeI32, false)
+	b.AddGlobal(types.ValueTypeI64, true, types.NewI64Const(7))
+	bs, err := b.Bytes()
+	assert.Nil(t, err)
+	imported, err := decode.DecodeBytes(bs)
+	assert.Nil(t, err)
+
+	g, err := imported.Global(0)
+	assert.Nil(t, err)
+	assert.True(t, g.Imported())
+	assert.Equal(t, "env", g.Import.Module)
+	assert.Equal(t, types.ValueTypeI32, g.Type.Value)
+	g, err = imported.Global(1)
+	assert.Nil(t, err)
+	assert.False(t, g.Imported())
+	assert.Equal(t, types.NewI64Const(7), g.Init)
+}
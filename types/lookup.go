@@ -0,0 +1,92 @@
+package types
+
+import (
+	"fmt"
+	"github.com/LBruyne/wasm-decode/common"
+)
+
+// Export is an export resolved in the index space of its kind, the one of Function, Table, Memory and Global
+// matching Kind is set
+type Export struct {
+	Name  string
+	Kind  ExternalKind
+	Index uint32
+
+	Function *Function
+	Table    *Table
+	Memory   *Memory
+	Global   *Global
+}
+
+// Import returns the import which e re-exports, or nil if e exports a component defined by the module
+func (e *Export) Import() *ImportSegment {
+	switch {
+	case e.Function != nil:
+		return e.Function.Import
+	case e.Table != nil:
+		return e.Table.Import
+	case e.Memory != nil:
+		return e.Memory.Import
+	case e.Global != nil:
+		return e.Global.Import
+	}
+	return nil
+}
+
+// Reexported reports whether e exports an imported component
+func (e *Export) Reexported() bool {
+	return e.Import() != nil
+}
+
+// ExportByName returns the export of name resolved in the index space of its kind
+func (m *Module) ExportByName(name string) (*Export, error) {
+	for _, exp := range m.SecExport {
+		if exp.Name == name {
+			return m.resolveExport(exp)
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", common.ErrExportNotFound, name)
+}
+
+func (m *Module) resolveExport(exp *ExportSegment) (ret *Export, err error) {
+	ret = &Export{Name: exp.Name, Kind: exp.Desc.Kind, Index: exp.Desc.Index}
+	switch exp.Desc.Kind {
+	case ExternalKindFunc:
+		ret.Function, err = m.Function(exp.Desc.Index)
+	case ExternalKindTable:
+		ret.Table, err = m.Table(exp.Desc.Index)
+	case ExternalKindMemory:
+		ret.Memory, err = m.Memory(exp.Desc.Index)
+	case ExternalKindGlobal:
+		ret.Global, err = m.Global(exp.Desc.Index)
+	default:
+		err = fmt.Errorf("invalid kind of export: %v", exp.Desc.Kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolve export %q: %w", exp.Name, err)
+	}
+	return ret, nil
+}
+
+// ImportsFrom returns the imports of module name in order
+func (m *Module) ImportsFrom(name string) []*ImportSegment {
+	var ret []*ImportSegment
+	for _, imp := range m.SecImport {
+		if imp.Module == name {
+			ret = append(ret, imp)
+		}
+	}
+	return ret
+}
+
+// FuncSignature returns the type of the function at funcIdx of the index space of functions
+func (m *Module) FuncSignature(funcIdx uint32) (*FunctionType, error) {
+	f, err := m.Function(funcIdx)
+	if err != nil {
+		return nil, err
+	}
+	if f.Type == nil {
+		return nil, fmt.Errorf("%w: type %d of function %d, %d types", common.ErrIndexOutOfRange, f.TypeIndex, funcIdx, len(m.SecType))
+	}
+	return f.Type, nil
+}
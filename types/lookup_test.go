@@ -0,0 +1,53 @@
+package types_test
+
+import (
+	"errors"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/decode"
+	"github.com/LBruyne/wasm-decode/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	mod, err := decode.DecodeBytes(externalsModule(t))
+	assert.Nil(t, err)
+
+	exp, err := mod.ExportByName("f")
+	assert.Nil(t, err)
+	assert.Equal(t, types.ExternalKindFunc, exp.Kind)
+	assert.False(t, exp.Reexported())
+	if assert.NotNil(t, exp.Function) {
+		assert.Equal(t, uint32(1), exp.Function.Index)
+		assert.Equal(t, mod.SecCode[0], exp.Function.Code)
+	}
+
+	_, err = mod.ExportByName("g")
+	assert.True(t, errors.Is(err, common.ErrExportNotFound))
+
+	// export the imported function instead
+	mod.SecExport[0].Desc.Index = 0
+	exp, err = mod.ExportByName("f")
+	assert.Nil(t, err)
+	assert.True(t, exp.Reexported())
+	assert.Equal(t, mod.SecImport[0], exp.Import())
+
+	mod.SecExport[0].Desc.Index = 2
+	_, err = mod.ExportByName("f")
+	assert.True(t, errors.Is(err, common.ErrIndexOutOfRange))
+
+	assert.Equal(t, mod.SecImport, mod.ImportsFrom("env"))
+	assert.Empty(t, mod.ImportsFrom("wasi"))
+
+	for idx := uint32(0); idx < 2; idx++ {
+		sig, err := mod.FuncSignature(idx)
+		assert.Nil(t, err)
+		assert.Equal(t, mod.SecType[0], sig)
+	}
+	_, err = mod.FuncSignature(2)
+	assert.True(t, errors.Is(err, common.ErrIndexOutOfRange))
+
+	mod.SecFunction[0] = 1
+	_, err = mod.FuncSignature(1)
+	assert.True(t, errors.Is(err, common.ErrIndexOutOfRange))
+}
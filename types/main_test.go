@@ -0,0 +1,6 @@
+package types_test
+
+var (
+	exampleFile = "../examples/wasm/test.wasm"
+	fibFile     = "../examples/wasm/fib.wasm"
+)
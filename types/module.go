@@ -1,6 +1,7 @@
 package types
 
 import (
+	"context"
 	"fmt"
 	"github.com/LBruyne/wasm-decode/common"
 	"github.com/LBruyne/wasm-decode/params"
@@ -12,25 +13,124 @@ type Module struct {
 	Version     []byte
 	MagicNumber []byte
 
-	SecType     []*FunctionType
-	SecFunction []uint32
-	SecTable    []*TableType
-	SecMemory   []*MemoryType
-	SecGlobal   []*GlobalSegment
-	SecElement  []*ElementSegment
-	SecData     []*DataSegment
-	SecStart    interface{}
-	SecImport   []*ImportSegment
-	SecExport   []*ExportSegment
-	SecCode     []*CodeSegment
-	SecCustom   *CustomSec
+	SecType      []*FunctionType
+	SecFunction  []uint32
+	SecTable     []*TableType
+	SecMemory    []*MemoryType
+	SecGlobal    []*GlobalSegment
+	SecElement   []*ElementSegment
+	SecData      []*DataSegment
+	SecStart     *uint32 // index of the start function, nil if there is none
+	SecDataCount *uint32
+	SecImport    []*ImportSegment
+	SecExport    []*ExportSegment
+	SecCode      []*CodeSegment
+	SecCustom    *CustomSec   // the last custom section
+	SecCustoms   []*CustomSec // all the custom sections in order
+
+	// Sections tells where each section is, in the order of the module binary
+	Sections []*SectionInfo
+	// UnknownSections are the sections of unknown id with their raw bytes,
+	// only if DecodeOptions.AllowUnknownSections or DecodeOptions.Recover is set
+	UnknownSections []*SectionInfo
+
+	// NonCanonicalLEBs lists the padded integers, only if DecodeOptions.ReportNonCanonicalLEB
+	// or DecodeOptions.KeepRawSections is set
+	NonCanonicalLEBs []*NonCanonicalLEB
+
+	// Diagnostics are the errors skipped in order, only if DecodeOptions.Recover is set
+	Diagnostics []*Diagnostic
+}
+
+// Diagnostic is an error met in a section which is skipped by DecodeOptions.Recover
+type Diagnostic struct {
+	Section SectionID
+	Offset  int64 // offset where the decoding failed
+	Err     error
+}
+
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("section %d at offset %#x: %v", d.Section, d.Offset, d.Err)
+}
+
+func (d *Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// DecodeError is the error of a module which fails to decode
+type DecodeError struct {
+	Offset int64 // offset of the input where the decoding stopped
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("at offset %#x: %v", e.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
 }
 
 // Decode decodes a wasm module from io.Reader which contains full bytecodes of .wasm file
 func (m *Module) Decode(r io.Reader) error {
+	return m.DecodeWithOptions(r, nil)
+}
+
+// DecodeWithOptions is like Decode but decodes according to opts, nil means DefaultDecodeOptions
+func (m *Module) DecodeWithOptions(r io.Reader, opts *DecodeOptions) error {
+	return m.decode(newStreamReader(r, opts))
+}
+
+// DecodeContext is like DecodeWithOptions but stops with the error of ctx once it is done,
+// which is checked between sections and between code segments
+func (m *Module) DecodeContext(ctx context.Context, r io.Reader, opts *DecodeOptions) error {
+	rd := newStreamReader(r, opts)
+	rd.ctx = ctx
+	return m.decode(rd)
+}
+
+// DecodeBytes decodes a wasm module from the full bytecodes of .wasm file.
+// Code bodies, data segments and custom section bytes of the module are sub-slices of b,
+// so b must not be modified while the module is in use.
+func (m *Module) DecodeBytes(b []byte) error {
+	return m.DecodeBytesWithOptions(b, nil)
+}
+
+// DecodeBytesWithOptions is like DecodeBytes but decodes according to opts, nil means DefaultDecodeOptions
+func (m *Module) DecodeBytesWithOptions(b []byte, opts *DecodeOptions) error {
+	return m.decode(newBytesReader(b, opts))
+}
+
+// DecodeBytesContext is like DecodeBytesWithOptions but stops with the error of ctx once it is done
+func (m *Module) DecodeBytesContext(ctx context.Context, b []byte, opts *DecodeOptions) error {
+	r := newBytesReader(b, opts)
+	r.ctx = ctx
+	return m.decode(r)
+}
+
+// DecodeReaderAt decodes a wasm module of size bytes from io.ReaderAt, e.g. a memory-mapped file.
+// Code bodies and data segments are not read during decoding, only their offsets are recorded,
+// they are loaded on demand by CodeSegment.LoadBody and DataSegment.LoadInit.
+func (m *Module) DecodeReaderAt(ra io.ReaderAt, size int64) error {
+	return m.DecodeReaderAtWithOptions(ra, size, nil)
+}
+
+// DecodeReaderAtWithOptions is like DecodeReaderAt but decodes according to opts, nil means DefaultDecodeOptions
+func (m *Module) DecodeReaderAtWithOptions(ra io.ReaderAt, size int64, opts *DecodeOptions) error {
+	return m.decode(newLazyReader(ra, size, opts))
+}
+
+func (m *Module) decode(r *reader) error {
+	if err := m.decodeModule(r); err != nil {
+		return &DecodeError{Offset: r.off, Err: err}
+	}
+	return nil
+}
+
+func (m *Module) decodeModule(r *reader) error {
 	// magic number
-	buf := make([]byte, 4)
-	if n, err := io.ReadFull(r, buf); err != nil || n != 4 {
+	var buf [4]byte
+	if err := r.readFull(buf[:]); err != nil {
 		return common.ErrInvalidMagicNumber
 	}
 	for i := 0; i < 4; i++ {
@@ -41,7 +141,7 @@ func (m *Module) Decode(r io.Reader) error {
 	m.MagicNumber = params.MagicNumber
 
 	// version
-	if n, err := io.ReadFull(r, buf); err != nil || n != 4 {
+	if err := r.readFull(buf[:]); err != nil {
 		return err
 	}
 	for i := 0; i < 4; i++ {
@@ -55,5 +155,21 @@ func (m *Module) Decode(r io.Reader) error {
 	if err := m.readSections(r); err != nil {
 		return fmt.Errorf("readSections failed: %w", err)
 	}
+	m.NonCanonicalLEBs = r.nonCanonical
+	return nil
+}
+
+// SectionAt returns the section containing the byte at offset off of the module binary, or nil
+func (m *Module) SectionAt(off int64) *SectionInfo {
+	for _, s := range m.Sections {
+		if off >= s.Offset && off < s.End() {
+			return s
+		}
+	}
 	return nil
 }
+
+// importedFuncCount count the number of imported functions
+func (m *Module) importedFuncCount() uint32 {
+	return m.ImportedCount(ExternalKindFunc)
+}
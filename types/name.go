@@ -0,0 +1,59 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SafeName renders a name of the module so that it can be printed to a terminal or a log.
+// Bytes of invalid UTF-8 are escaped as \xNN, control and format characters such as
+// escape sequences or bidirectional overrides as \uNNNN, and backslash as \\.
+func SafeName(name string) string {
+	if isSafeASCII(name) {
+		return name
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(name); {
+		c, size := utf8.DecodeRuneInString(name[i:])
+		switch {
+		case c == utf8.RuneError && size == 1:
+			fmt.Fprintf(&sb, `\x%02x`, name[i])
+		case c == '\\':
+			sb.WriteString(`\\`)
+		case unicode.IsPrint(c):
+			sb.WriteString(name[i : i+size])
+		case c <= 0xffff:
+			fmt.Fprintf(&sb, `\u%04x`, c)
+		default:
+			fmt.Fprintf(&sb, `\U%08x`, c)
+		}
+		i += size
+	}
+	return sb.String()
+}
+
+// isSafeASCII reports whether s contains printable ASCII only, other than backslash
+func isSafeASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e || s[i] == '\\' {
+			return false
+		}
+	}
+	return true
+}
+
+// invalidUTF8Index returns the index of the first byte of s which is not valid UTF-8, or -1
+func invalidUTF8Index(s string) int {
+	for i, c := range s {
+		if c != utf8.RuneError {
+			continue
+		}
+		if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+			return i
+		}
+	}
+	return -1
+}
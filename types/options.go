@@ -0,0 +1,79 @@
+package types
+
+import "github.com/LBruyne/wasm-decode/feature"
+
+// DecodeOptions controls how a module is decoded.
+//
+// The limits protect the decoder from untrusted input, which may declare huge vectors,
+// strings or locals in a few bytes. A zero limit means no limit.
+type DecodeOptions struct {
+	// Features is the set of proposals accepted besides WASM 1.0, using a disabled one in a type, a section
+	// or an instruction fails the decoding. The bodies decoded lazily are checked once loaded.
+	// ExtendedConst changes nothing, since a constant expression of several instructions is never accepted.
+	Features feature.Set
+	// AllowUnknownSections makes sections of unknown id, such as ones of newer proposals,
+	// kept in Module.UnknownSections instead of failing the decoding with UnknownSectionError
+	AllowUnknownSections bool
+	// AllowInvalidUTF8 makes names of invalid UTF-8 kept with InvalidName set instead of failing the decoding
+	AllowInvalidUTF8 bool
+	// ReportNonCanonicalLEB makes integers encoded with more bytes than needed recorded in Module.NonCanonicalLEBs
+	ReportNonCanonicalLEB bool
+	// KeepRawSections makes the bytes of each section kept in SectionInfo.Raw, and the padded integers
+	// reported as by ReportNonCanonicalLEB, so that the module binary can be reproduced exactly
+	KeepRawSections bool
+
+	// CustomSectionDecoders parse custom sections by name into CustomSec.Value, besides and taking precedence over
+	// the ones of RegisterCustomSectionDecoder. A nil decoder leaves the sections of its name undecoded.
+	CustomSectionDecoders map[string]CustomSectionDecoder
+	// StrictCustomSections makes an error of a custom section decoder fail the decoding,
+	// instead of being kept in CustomSec.Err
+	StrictCustomSections bool
+
+	// Recover makes a section which fails to decode skipped by its declared size, and a code segment
+	// by its length prefix, with the error kept in Module.Diagnostics instead of failing the decoding.
+	// A skipped code segment is left nil in Module.SecCode, other sections keep the elements read.
+	Recover bool
+
+	// Progress is called after each section and each code segment
+	Progress ProgressFunc
+
+	// Visitor visits the components of each section once it is decoded, in the order of Walk.
+	// The code segments are visited one by one and left nil in Module.SecCode, so that the bodies are
+	// not kept. StopWalk returned by the visitor stops the decoding with an error wrapping it.
+	Visitor Visitor
+
+	// MaxVectorLen limits the number of elements of any vector
+	MaxVectorLen uint32
+	// MaxStringLen limits the length in bytes of names and strings
+	MaxStringLen uint32
+	// MaxFunctions limits the number of functions, including the imported ones
+	MaxFunctions uint32
+	// MaxLocals limits the number of locals declared by one function
+	MaxLocals uint32
+	// MaxFunctionSize limits the size in bytes of one code segment
+	MaxFunctionSize uint32
+	// MaxCustomSectionBytes limits the total size of custom sections
+	MaxCustomSectionBytes uint64
+	// MaxAllocBytes limits the approximate memory allocated for the decoded module
+	MaxAllocBytes uint64
+}
+
+// ProgressFunc receives the id of the section being decoded, the number of bytes read
+// and the size of the module binary, which is -1 if it is unknown
+type ProgressFunc func(id SectionID, read int64, total int64)
+
+// DefaultDecodeOptions returns the options used when none is given.
+// It accepts WASM 2.0 and the limits follow the ones enforced by web engines,
+// which every real module stays within.
+func DefaultDecodeOptions() *DecodeOptions {
+	return &DecodeOptions{
+		Features:              feature.Wasm20,
+		MaxVectorLen:          10000000,
+		MaxStringLen:          100000,
+		MaxFunctions:          1000000,
+		MaxLocals:             50000,
+		MaxFunctionSize:       7654321,
+		MaxCustomSectionBytes: 1 << 26,
+		MaxAllocBytes:         1 << 30,
+	}
+}
@@ -0,0 +1,528 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/feature"
+	"io"
+	"io/ioutil"
+	"unsafe"
+)
+
+// reader is the cursor over the input of a module and keeps track of the offset of the next byte.
+// It works in one of three modes:
+//   - stream: bytes come from an io.Reader, through a bufio.Reader unless it reads byte by byte already,
+//     payloads are copied out
+//   - bytes: the input is a []byte, payloads are sub-slices of it (zero-copy)
+//   - lazy: the input is an io.ReaderAt, large payloads are only recorded by offset
+//     and loaded on demand
+//
+// Every readX function reads through it, so that no allocation is made per byte or per integer.
+// It also enforces the limits of DecodeOptions while reading.
+type reader struct {
+	r    byteScanner // nil in bytes mode
+	b    []byte      // input of bytes mode
+	ra   io.ReaderAt // input of lazy mode
+	off  int64
+	size int64 // size of the input, -1 if unknown
+
+	ctx         context.Context // nil if the decoding cannot be canceled
+	opts        *DecodeOptions
+	allocated   uint64 // approximate bytes allocated so far
+	customBytes uint64 // total size of custom sections so far
+
+	capDepth int    // number of captures in progress, which may be nested
+	capBuf   []byte // bytes captured in stream and lazy modes, followed by free space for the next capture
+
+	strs    string // copy of the current section in bytes mode, names are sliced from it
+	strsOff int64  // offset of strs in the input
+
+	slabs  slabs
+	walker *walker // visits the sections once decoded, nil without DecodeOptions.Visitor
+
+	nonCanonical []*NonCanonicalLEB
+}
+
+// byteScanner is the input of stream and lazy modes
+type byteScanner interface {
+	io.Reader
+	io.ByteScanner
+}
+
+// maxReadChunk is the most bytes allocated ahead of reading them when the size of the input is unknown,
+// so that a declared size cannot make more memory allocated than the input has
+const maxReadChunk = 64 << 10
+
+// defaultOptions is used when no options are given, it is never modified
+var defaultOptions = DefaultDecodeOptions()
+
+func newStreamReader(r io.Reader, opts *DecodeOptions) *reader {
+	bs, ok := r.(byteScanner)
+	if !ok {
+		bs = bufio.NewReader(r)
+	}
+	return &reader{r: bs, size: -1, opts: optionsOrDefault(opts)}
+}
+
+func newBytesReader(b []byte, opts *DecodeOptions) *reader {
+	return &reader{b: b, size: int64(len(b)), opts: optionsOrDefault(opts)}
+}
+
+func newLazyReader(ra io.ReaderAt, size int64, opts *DecodeOptions) *reader {
+	return &reader{
+		r:    bufio.NewReader(io.NewSectionReader(ra, 0, size)),
+		ra:   ra,
+		size: size,
+		opts: optionsOrDefault(opts),
+	}
+}
+
+func optionsOrDefault(opts *DecodeOptions) *DecodeOptions {
+	if opts == nil {
+		return defaultOptions
+	}
+	return opts
+}
+
+// lazy reports whether large payloads should be recorded instead of read
+func (r *reader) lazy() bool {
+	return r.ra != nil
+}
+
+// Read and ReadByte report io.ErrUnexpectedEOF at the end of the input,
+// since only atEOF tells where a module may end
+func (r *reader) Read(p []byte) (int, error) {
+	if r.r != nil {
+		n, err := r.r.Read(p)
+		if err == io.EOF && n == 0 {
+			err = io.ErrUnexpectedEOF
+		}
+		r.off += int64(n)
+		if r.capDepth > 0 {
+			r.capBuf = append(r.capBuf, p[:n]...)
+		}
+		return n, err
+	}
+
+	if r.off >= int64(len(r.b)) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	n := copy(p, r.b[r.off:])
+	r.off += int64(n)
+	return n, nil
+}
+
+func (r *reader) ReadByte() (byte, error) {
+	if r.r == nil {
+		if r.off >= int64(len(r.b)) {
+			return 0, io.ErrUnexpectedEOF
+		}
+		r.off++
+		return r.b[r.off-1], nil
+	}
+
+	c, err := r.r.ReadByte()
+	if err == io.EOF {
+		return 0, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return 0, err
+	}
+	r.off++
+	if r.capDepth > 0 {
+		r.capBuf = append(r.capBuf, c)
+	}
+	return c, nil
+}
+
+// atEOF reports whether all the input is read
+func (r *reader) atEOF() (bool, error) {
+	if r.r == nil {
+		return r.off >= int64(len(r.b)), nil
+	}
+
+	if _, err := r.r.ReadByte(); err == io.EOF {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	return false, r.r.UnreadByte()
+}
+
+// readFull read len(buf) bytes into buf
+func (r *reader) readFull(buf []byte) error {
+	for i := range buf {
+		c, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		buf[i] = c
+	}
+	return nil
+}
+
+// capture marks where capturing starts
+type capture struct {
+	off int64 // offset in the input
+	idx int   // index in capBuf
+}
+
+// startCapture starts to record the bytes read until stopCapture, size is the expected number of bytes.
+// Captures may be nested, the inner ones share the bytes of the outer ones.
+func (r *reader) startCapture(size int) capture {
+	c := capture{off: r.off, idx: len(r.capBuf)}
+	if r.r != nil {
+		r.capDepth++
+		r.growCapture(size)
+	}
+	return c
+}
+
+// growCapture makes room for n more bytes to capture
+func (r *reader) growCapture(n int) {
+	if cap(r.capBuf)-len(r.capBuf) >= n {
+		return
+	}
+
+	c := len(r.capBuf) + n
+	if c < 512 {
+		c = 512
+	}
+	buf := make([]byte, len(r.capBuf), c)
+	copy(buf, r.capBuf)
+	r.capBuf = buf
+}
+
+// stopCapture returns the bytes read since c started
+func (r *reader) stopCapture(c capture) []byte {
+	if r.r == nil {
+		return r.b[c.off:r.off:r.off]
+	}
+
+	n := len(r.capBuf)
+	buf := r.capBuf[c.idx:n:n]
+	r.capDepth--
+	if r.capDepth == 0 {
+		r.capBuf = r.capBuf[n:]
+	}
+	return buf
+}
+
+// readUint32 read an unsigned LEB128 integer of 32 bits
+func (r *reader) readUint32() (v uint32, err error) {
+	start := r.off
+	if r.r == nil {
+		var n int
+		v, n, err = common.DecodeUint32Bytes(r.b[r.off:])
+		r.off += int64(n)
+	} else {
+		v, _, err = common.DecodeUint32(r)
+	}
+	if err != nil {
+		return 0, err
+	}
+	r.checkCanonical(start, common.SizeUint64(uint64(v)))
+	return v, nil
+}
+
+// readUint64 read an unsigned LEB128 integer of 64 bits
+func (r *reader) readUint64() (v uint64, err error) {
+	start := r.off
+	if r.r == nil {
+		var n int
+		v, n, err = common.DecodeUint64Bytes(r.b[r.off:])
+		r.off += int64(n)
+	} else {
+		v, _, err = common.DecodeUint64(r)
+	}
+	if err != nil {
+		return 0, err
+	}
+	r.checkCanonical(start, common.SizeUint64(v))
+	return v, nil
+}
+
+// readInt32 read a signed LEB128 integer of 32 bits
+func (r *reader) readInt32() (v int32, err error) {
+	start := r.off
+	if r.r == nil {
+		var n int
+		v, n, err = common.DecodeInt32Bytes(r.b[r.off:])
+		r.off += int64(n)
+	} else {
+		v, _, err = common.DecodeInt32(r)
+	}
+	if err != nil {
+		return 0, err
+	}
+	r.checkCanonical(start, common.SizeInt64(int64(v)))
+	return v, nil
+}
+
+// readInt64 read a signed LEB128 integer of 64 bits
+func (r *reader) readInt64() (v int64, err error) {
+	start := r.off
+	if r.r == nil {
+		var n int
+		v, n, err = common.DecodeInt64Bytes(r.b[r.off:])
+		r.off += int64(n)
+	} else {
+		v, _, err = common.DecodeInt64(r)
+	}
+	if err != nil {
+		return 0, err
+	}
+	r.checkCanonical(start, common.SizeInt64(v))
+	return v, nil
+}
+
+// checkCanonical records the integer read from start if it takes more bytes than minWidth
+func (r *reader) checkCanonical(start int64, minWidth int) {
+	if !r.opts.ReportNonCanonicalLEB && !r.opts.KeepRawSections {
+		return
+	}
+	if w := int(r.off - start); w > minWidth {
+		r.nonCanonical = append(r.nonCanonical, &NonCanonicalLEB{
+			Offset:   start,
+			Width:    w,
+			MinWidth: minWidth,
+		})
+	}
+}
+
+// remaining returns the number of unread bytes, or -1 if it is unknown
+func (r *reader) remaining() int64 {
+	if r.size < 0 {
+		return -1
+	}
+	return r.size - r.off
+}
+
+// checkContext returns the error of the context once it is done
+func (r *reader) checkContext() error {
+	if r.ctx == nil {
+		return nil
+	}
+	if err := r.ctx.Err(); err != nil {
+		return fmt.Errorf("decoding stopped at offset %#x: %w", r.off, err)
+	}
+	return nil
+}
+
+// progress reports the bytes read so far to DecodeOptions.Progress
+func (r *reader) progress(id SectionID) {
+	if r.opts.Progress != nil {
+		r.opts.Progress(id, r.off, r.size)
+	}
+}
+
+// require returns an error if f is not enabled, what describes the construct requiring it
+func (r *reader) require(f feature.Feature, what string) error {
+	if !r.opts.Features.Has(f) {
+		return fmt.Errorf("%w: %s requires %s", common.ErrFeatureDisabled, what, f)
+	}
+	return nil
+}
+
+// alloc accounts n bytes of memory which are going to be allocated
+func (r *reader) alloc(n uint64) error {
+	r.allocated += n
+	if max := r.opts.MaxAllocBytes; max != 0 && r.allocated > max {
+		return fmt.Errorf("%w: %d > %d bytes", common.ErrAllocLimitExceeded, r.allocated, max)
+	}
+	return nil
+}
+
+// readVectorSize read the size of a vector whose elements take elemSize bytes of memory each
+func (r *reader) readVectorSize(elemSize uintptr) (uint32, error) {
+	vs, err := r.readUint32()
+	if err != nil {
+		return 0, err
+	}
+
+	if max := r.opts.MaxVectorLen; max != 0 && vs > max {
+		return 0, fmt.Errorf("%w: %d > %d", common.ErrVectorTooLong, vs, max)
+	}
+	// each element takes at least one byte of the input
+	if rem := r.remaining(); rem >= 0 && int64(vs) > rem {
+		return 0, fmt.Errorf("%w: %d elements but %d bytes left", common.ErrVectorTooLong, vs, rem)
+	}
+	if err := r.alloc(uint64(vs) * uint64(elemSize)); err != nil {
+		return 0, err
+	}
+	return vs, nil
+}
+
+// readString read a string prefixed by its size
+func (r *reader) readString() (string, error) {
+	vs, err := r.readUint32()
+	if err != nil {
+		return "", fmt.Errorf("read size of string: %w", err)
+	}
+
+	if max := r.opts.MaxStringLen; max != 0 && vs > max {
+		return "", fmt.Errorf("%w: %d > %d", common.ErrStringTooLong, vs, max)
+	}
+
+	start := r.off
+	buf, err := r.readBytes(vs)
+	if err != nil {
+		return "", fmt.Errorf("read bytes of string: %w", err)
+	}
+
+	if r.r != nil {
+		// buf is copied out of the input into a slab and nothing else refers to it
+		return *(*string)(unsafe.Pointer(&buf)), nil
+	}
+	if start >= r.strsOff && r.off <= r.strsOff+int64(len(r.strs)) {
+		return r.strs[start-r.strsOff : r.off-r.strsOff], nil
+	}
+	if err := r.alloc(uint64(vs)); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// internStrings copies the next n bytes into one string in bytes mode,
+// so that the names read from them are sliced from it instead of allocated one by one
+func (r *reader) internStrings(n uint32) error {
+	if r.r != nil {
+		return nil
+	}
+	if err := r.alloc(uint64(n)); err != nil {
+		return err
+	}
+
+	end := r.off + int64(n)
+	if end > int64(len(r.b)) {
+		end = int64(len(r.b))
+	}
+	r.strs = string(r.b[r.off:end])
+	r.strsOff = r.off
+	return nil
+}
+
+// readName read a name, which must be valid UTF-8. If AllowInvalidUTF8 is set,
+// an invalid name is kept and valid is false
+func (r *reader) readName() (name string, valid bool, err error) {
+	name, err = r.readString()
+	if err != nil {
+		return "", false, err
+	}
+
+	valid, err = r.validateName(name, r.off-int64(len(name)))
+	return name, valid, err
+}
+
+// validateName checks the name read from start is valid UTF-8
+func (r *reader) validateName(name string, start int64) (bool, error) {
+	i := invalidUTF8Index(name)
+	if i < 0 {
+		return true, nil
+	}
+	if r.opts.AllowInvalidUTF8 {
+		return false, nil
+	}
+	return false, fmt.Errorf("%w: byte %#x at offset %#x", common.ErrInvalidUTF8, name[i], start+int64(i))
+}
+
+// readBytes read n bytes, which share the memory of the input in bytes mode
+func (r *reader) readBytes(n uint32) ([]byte, error) {
+	if r.r != nil {
+		if rem := r.remaining(); rem >= 0 && int64(n) > rem {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err := r.alloc(uint64(n)); err != nil {
+			return nil, err
+		}
+		if r.remaining() < 0 && n > maxReadChunk {
+			return r.readChunks(n)
+		}
+
+		buf := r.slabs.newBytes(n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf, nil
+	}
+
+	end := r.off + int64(n)
+	if end > int64(len(r.b)) {
+		r.off = int64(len(r.b))
+		return nil, io.ErrUnexpectedEOF
+	}
+	buf := r.b[r.off:end:end]
+	r.off = end
+	return buf, nil
+}
+
+// readChunks read n bytes in stream mode, the buffer grows as the bytes arrive
+func (r *reader) readChunks(n uint32) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(maxReadChunk)
+	if _, err := io.CopyN(&buf, r, int64(n)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes()[:n:n], nil
+}
+
+// skip discards the next n bytes
+func (r *reader) skip(n uint32) error {
+	if r.r == nil {
+		_, err := r.readBytes(n)
+		return err
+	}
+
+	var c int64
+	var err error
+	if d, ok := r.r.(interface{ Discard(int) (int, error) }); ok && r.capDepth == 0 {
+		var dn int
+		dn, err = d.Discard(int(n))
+		c = int64(dn)
+	} else {
+		c, err = io.CopyN(ioutil.Discard, r, int64(n))
+		r.off -= c // counted by r.Read already
+	}
+	r.off += c
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return fmt.Errorf("skip %d bytes, only %d skipped: %w", n, c, err)
+	}
+	return nil
+}
+
+// seek moves to offset off of the input, which may be backwards only in bytes and lazy modes
+func (r *reader) seek(off int64) error {
+	switch {
+	case off >= r.off:
+		return r.skip(uint32(off - r.off))
+	case r.r == nil:
+		r.off = off
+		return nil
+	case r.lazy():
+		r.r = bufio.NewReader(io.NewSectionReader(r.ra, off, r.size-off))
+		r.off = off
+		return nil
+	}
+	return fmt.Errorf("seek back from %#x to %#x in stream mode", r.off, off)
+}
+
+// loadAt read size bytes from ra starting at off
+func loadAt(ra io.ReaderAt, off int64, size int64) ([]byte, error) {
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(io.NewSectionReader(ra, off, size), buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// NonCanonicalLEB is an integer encoded with more bytes than needed, which is valid but padded
+type NonCanonicalLEB struct {
+	Offset   int64 // offset of the integer in the module binary
+	Width    int   // number of bytes it takes
+	MinWidth int   // number of bytes of the shortest encoding
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/feature"
 	"io"
+	"unsafe"
 )
 
 type SectionID byte
@@ -22,42 +24,169 @@ const (
 	SectionIDElement  SectionID = 9
 	SectionIDCode     SectionID = 10
 	SectionIDData     SectionID = 11
+
+	// SectionIDDataCount is defined by bulk memory proposal
+	SectionIDDataCount SectionID = 12
 )
 
+var sectionNames = [...]string{"custom", "type", "import", "function", "table", "memory", "global",
+	"export", "start", "element", "code", "data", "datacount"}
+
+// String returns the name of the section of id, e.g. "import"
+func (id SectionID) String() string {
+	if id.known() {
+		return sectionNames[id]
+	}
+	return fmt.Sprintf("section(%d)", byte(id))
+}
+
+// known reports whether the sections of the id are decoded
+func (id SectionID) known() bool {
+	return id <= SectionIDDataCount
+}
+
+// UnknownSectionError is the error of a section of unknown id, which wraps common.ErrUnknownSection
+type UnknownSectionError struct {
+	ID     SectionID
+	Offset int64 // offset of the section id
+	Size   uint32
+}
+
+func (e *UnknownSectionError) Error() string {
+	return fmt.Sprintf("%v %d at offset %#x", common.ErrUnknownSection, e.ID, e.Offset)
+}
+
+func (e *UnknownSectionError) Unwrap() error {
+	return common.ErrUnknownSection
+}
+
 // readSections read each section continuously until the end of file or meet an error
-func (m *Module) readSections(r io.Reader) error {
+func (m *Module) readSections(r *reader) error {
 	for {
-		// read each section
-		if err := m.readSection(r); errors.Is(err, io.EOF) {
+		if end, err := r.atEOF(); err != nil {
+			return fmt.Errorf("read section id: %w", err)
+		} else if end {
 			return nil
-		} else if err != nil {
+		}
+
+		if err := r.checkContext(); err != nil {
 			return err
 		}
+
+		// read each section
+		if err := m.readSection(r); err != nil {
+			return err
+		}
+	}
+}
+
+// SectionInfo tells where a section is in the module binary
+type SectionInfo struct {
+	ID            SectionID
+	Offset        int64  // offset of the section id
+	PayloadOffset int64  // offset of the payload, which follows the size
+	Size          uint32 // declared size of the payload
+
+	// Raw is the whole section as it is in the module binary, from the section id to the end of the payload.
+	// It is kept only if DecodeOptions.KeepRawSections is set, and loaded by LoadRaw for lazy decoding.
+	Raw []byte
+
+	src io.ReaderAt // where to load Raw from if it is not read yet
+}
+
+// End returns the offset following the section
+func (s *SectionInfo) End() int64 {
+	return s.PayloadOffset + int64(s.Size)
+}
+
+// SizeWidth returns the number of bytes taken by the encoded size
+func (s *SectionInfo) SizeWidth() int {
+	return int(s.PayloadOffset - s.Offset - 1)
+}
+
+// LoadRaw returns Raw of the section, which is read from the underlying io.ReaderAt
+// if the module is decoded lazily
+func (s *SectionInfo) LoadRaw() ([]byte, error) {
+	if s.src == nil {
+		return s.Raw, nil
+	}
+
+	raw, err := loadAt(s.src, s.Offset, s.End()-s.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("load section at %d: %w", s.Offset, err)
 	}
+
+	s.Raw, s.src = raw, nil
+	return s.Raw, nil
 }
 
 // readSection read each section according to the section id
-func (m *Module) readSection(r io.Reader) error {
+func (m *Module) readSection(r *reader) error {
+	info := r.slabs.newSectionInfo()
+	info.Offset = r.off
+	depth := r.capDepth
+	// capture the header in case the section is kept
+	c := r.startCapture(16)
+
 	// read section id
-	b := make([]byte, 1)
-	if _, err := io.ReadFull(r, b); err != nil {
+	id, err := r.ReadByte()
+	if err != nil {
+		r.stopCapture(c)
 		return fmt.Errorf("read section id: %w", err)
 	}
 
 	// read section size
-	ss, _, err := common.DecodeUint32(r)
+	ss, err := r.readUint32()
 	if err != nil {
-		return fmt.Errorf("get size of section for id=%d: %w", SectionID(b[0]), err)
+		r.stopCapture(c)
+		return fmt.Errorf("get size of section for id=%d: %w", SectionID(id), err)
+	}
+
+	info.ID, info.PayloadOffset, info.Size = SectionID(id), r.off, ss
+	if !info.ID.known() && !r.opts.AllowUnknownSections {
+		err := &UnknownSectionError{ID: info.ID, Offset: info.Offset, Size: ss}
+		if !r.opts.Recover {
+			r.stopCapture(c)
+			return err
+		}
+		// kept as in AllowUnknownSections
+		m.Diagnostics = append(m.Diagnostics, &Diagnostic{Section: info.ID, Offset: info.Offset, Err: err})
+	}
+	if m.Sections == nil {
+		m.Sections = make([]*SectionInfo, 0, sectionSlab)
+	}
+	m.Sections = append(m.Sections, info)
+
+	// the declared size is only trusted as far as the input is known to have the bytes,
+	// otherwise the buffers grow as the bytes arrive
+	hint := int(ss)
+	if r.remaining() < 0 && hint > maxReadChunk {
+		hint = maxReadChunk
+	}
+	r.slabs.byteHint = hint
+	keepRaw := r.opts.KeepRawSections || !info.ID.known()
+	capturing := keepRaw && !r.lazy()
+	if capturing {
+		if err := r.alloc(uint64(ss)); err != nil {
+			r.stopCapture(c)
+			return err
+		}
+		r.growCapture(hint)
+		depth++
+	} else {
+		r.stopCapture(c)
 	}
 
 	// decode section according to its id
-	switch SectionID(b[0]) {
+	switch SectionID(id) {
 	case SectionIDCustom:
 		err = m.readSectionCustom(r, ss)
 	case SectionIDType:
 		err = m.readSectionType(r, ss)
 	case SectionIDImport:
-		err = m.readSectionImport(r, ss)
+		if err = r.internStrings(ss); err == nil {
+			err = m.readSectionImport(r, ss)
+		}
 	case SectionIDFunction:
 		err = m.readSectionFunction(r, ss)
 	case SectionIDTable:
@@ -67,7 +196,9 @@ func (m *Module) readSection(r io.Reader) error {
 	case SectionIDGlobal:
 		err = m.readSectionGlobal(r, ss)
 	case SectionIDExport:
-		err = m.readSectionExport(r, ss)
+		if err = r.internStrings(ss); err == nil {
+			err = m.readSectionExport(r, ss)
+		}
 	case SectionIDStart:
 		err = m.readSectionStart(r, ss)
 	case SectionIDElement:
@@ -76,12 +207,55 @@ func (m *Module) readSection(r io.Reader) error {
 		err = m.readSectionCode(r, ss)
 	case SectionIDData:
 		err = m.readSectionData(r, ss)
+	case SectionIDDataCount:
+		err = m.readSectionDataCount(r, ss)
 	default:
-		err = errors.New("invalid section id")
+		err = r.skip(ss)
+		m.UnknownSections = append(m.UnknownSections, info)
+	}
+
+	if err == nil && r.off != info.End() {
+		err = fmt.Errorf("%d bytes read but its size is %d", r.off-info.PayloadOffset, ss)
+	}
+	if err != nil && r.opts.Recover && r.checkContext() == nil {
+		err = m.recover(r, info, r.off, depth, fmt.Errorf("read section for %d: %w", SectionID(id), err))
+	}
+
+	if capturing {
+		info.Raw = r.stopCapture(c)
+	} else if keepRaw {
+		info.src = r.ra
 	}
 
 	if err != nil {
-		return fmt.Errorf("read section for %d: %w", SectionID(b[0]), err)
+		return fmt.Errorf("read section for %d: %w", SectionID(id), err)
+	}
+	if w := r.visitor(m); w != nil && info.ID != SectionIDCode {
+		if err := w.section(info.ID); err != nil {
+			return fmt.Errorf("visit section for %d: %w", SectionID(id), err)
+		}
+	}
+	r.progress(info.ID)
+	return nil
+}
+
+// visitor returns the walker of DecodeOptions.Visitor over m, or nil
+func (r *reader) visitor(m *Module) *walker {
+	if r.walker == nil && r.opts.Visitor != nil {
+		r.walker = &walker{m: m, v: r.opts.Visitor}
+	}
+	return r.walker
+}
+
+// recover records err met at offset off in the section as a diagnostic and moves to the end of the section,
+// depth is the number of captures in progress when the section started, the ones left by err are dropped.
+// A section truncated by the end of input ends the module.
+func (m *Module) recover(r *reader, info *SectionInfo, off int64, depth int, err error) error {
+	m.Diagnostics = append(m.Diagnostics, &Diagnostic{Section: info.ID, Offset: off, Err: err})
+	r.capDepth = depth
+
+	if err := r.seek(info.End()); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("recover from error at %#x: %w", off, err)
 	}
 	return nil
 }
@@ -89,210 +263,340 @@ func (m *Module) readSection(r io.Reader) error {
 type CustomSec struct {
 	Name  string
 	Bytes []byte
+
+	// Value is parsed from Bytes by the decoder of the section name, if any and it succeeds
+	Value interface{}
+	// Err is the error of the decoder of the section name, if any and it fails
+	Err error
+
+	// InvalidName is set if Name is not valid UTF-8, only if DecodeOptions.AllowInvalidUTF8 is set
+	InvalidName bool
 }
 
-func (m *Module) readSectionCustom(r io.Reader, ss uint32) error {
+func (m *Module) readSectionCustom(r *reader, ss uint32) error {
+	r.customBytes += uint64(ss)
+	if max := r.opts.MaxCustomSectionBytes; max != 0 && r.customBytes > max {
+		return fmt.Errorf("%w: %d > %d bytes", common.ErrCustomSectionsTooLarge, r.customBytes, max)
+	}
+
 	// get name
-	ns, n, err := common.DecodeUint32(r)
+	start := r.off
+	ns, err := r.readUint32()
 	if err != nil {
 		return fmt.Errorf("read size of custom section name: %w", err)
 	}
 
-	buf := make([]byte, ns)
-	if _, err := io.ReadFull(r, buf); err != nil {
+	if max := r.opts.MaxStringLen; max != 0 && ns > max {
+		return fmt.Errorf("%w: %d > %d", common.ErrStringTooLong, ns, max)
+	}
+	n := r.off - start
+	if uint64(ns)+uint64(n) > uint64(ss) {
+		return fmt.Errorf("custom section name of %d bytes exceeds section size %d", ns, ss)
+	}
+
+	buf, err := r.readBytes(ns)
+	if err != nil {
 		return fmt.Errorf("read bytes of custom section name: %w", err)
 	}
+	name := string(buf)
+	valid, err := r.validateName(name, r.off-int64(ns))
+	if err != nil {
+		return fmt.Errorf("read custom section name: %w", err)
+	}
 
-	ss -= ns + uint32(n) // TODO has risk to lose precision
+	ss -= ns + uint32(n)
 
-	bs := make([]byte, ss)
-	if _, err := io.ReadFull(r, bs); err != nil {
+	bs, err := r.readBytes(ss)
+	if err != nil {
 		return fmt.Errorf("read custom section bytes: %w", err)
 	}
 
-	m.SecCustom = &CustomSec{
-		Name:  string(buf),
-		Bytes: bs,
+	m.SecCustom = r.slabs.newCustomSec()
+	*m.SecCustom = CustomSec{
+		Name:        name,
+		Bytes:       bs,
+		InvalidName: !valid,
 	}
-	return nil
+	if m.SecCustoms == nil {
+		m.SecCustoms = make([]*CustomSec, 0, sectionSlab/4)
+	}
+	m.SecCustoms = append(m.SecCustoms, m.SecCustom)
+	return r.decodeCustomSection(m.SecCustom)
 }
 
-func (m *Module) readSectionType(r io.Reader, size uint32) error {
+func (m *Module) readSectionType(r *reader, size uint32) error {
 	// get the vector size
-	vs, _, err := common.DecodeUint32(r)
+	vs, err := r.readVectorSize(unsafe.Sizeof(FunctionType{}))
 	if err != nil {
 		return fmt.Errorf("get size of vector: %w", err)
 	}
+	r.slabs.hint = int(vs)
 
 	m.SecType = make([]*FunctionType, vs)
 	for i := range m.SecType {
 		m.SecType[i], err = readFunctionType(r)
 		if err != nil {
+			m.SecType = m.SecType[:i]
 			return fmt.Errorf("read %d-th function type: %w", i, err)
 		}
 	}
 	return nil
 }
 
-func (m *Module) readSectionImport(r io.Reader, size uint32) error {
+func (m *Module) readSectionImport(r *reader, size uint32) error {
 	// get the vector size
-	vs, _, err := common.DecodeUint32(r)
+	vs, err := r.readVectorSize(unsafe.Sizeof(ImportSegment{}))
 	if err != nil {
 		return fmt.Errorf("get size of vector: %w", err)
 	}
+	r.slabs.hint = int(vs)
 
 	m.SecImport = make([]*ImportSegment, vs)
 	for i := range m.SecImport {
 		m.SecImport[i], err = readImportSegment(r)
 		if err != nil {
+			m.SecImport = m.SecImport[:i]
 			return fmt.Errorf("read %v-th import segment: %w", i, err)
 		}
 	}
 	return nil
 }
 
-func (m *Module) readSectionFunction(r io.Reader, ss uint32) error {
+func (m *Module) readSectionFunction(r *reader, ss uint32) error {
 	// get the vector size
-	vs, _, err := common.DecodeUint32(r)
+	vs, err := r.readVectorSize(unsafe.Sizeof(uint32(0)))
 	if err != nil {
 		return fmt.Errorf("get size of vector: %w", err)
 	}
+	r.slabs.hint = int(vs)
+
+	if max := r.opts.MaxFunctions; max != 0 && uint64(m.importedFuncCount())+uint64(vs) > uint64(max) {
+		return fmt.Errorf("%w: %d imported and %d defined > %d", common.ErrTooManyFunctions, m.importedFuncCount(), vs, max)
+	}
 
 	m.SecFunction = make([]uint32, vs)
 	for i := range m.SecFunction {
-		m.SecFunction[i], _, err = common.DecodeUint32(r)
+		m.SecFunction[i], err = r.readUint32()
 		if err != nil {
+			m.SecFunction = m.SecFunction[:i]
 			return fmt.Errorf("read %v-th function's type index: %w", i, err)
 		}
 	}
 	return nil
 }
 
-func (m *Module) readSectionTable(r io.Reader, ss uint32) error {
+func (m *Module) readSectionTable(r *reader, ss uint32) error {
 	// get the vector size
-	vs, _, err := common.DecodeUint32(r)
+	vs, err := r.readVectorSize(unsafe.Sizeof(TableType{}))
 	if err != nil {
 		return fmt.Errorf("get size of vector: %w", err)
 	}
+	r.slabs.hint = int(vs)
+
+	if m.ImportedCount(ExternalKindTable)+vs > 1 {
+		if err := r.require(feature.ReferenceTypes, "multiple tables"); err != nil {
+			return err
+		}
+	}
 
 	m.SecTable = make([]*TableType, vs)
 	for i := range m.SecTable {
 		m.SecTable[i], err = readTableType(r)
 		if err != nil {
+			m.SecTable = m.SecTable[:i]
 			return fmt.Errorf("read %v-th table type: %w", i, err)
 		}
 	}
 	return nil
 }
 
-func (m *Module) readSectionMemory(r io.Reader, ss uint32) error {
+func (m *Module) readSectionMemory(r *reader, ss uint32) error {
 	// get the vector size
-	vs, _, err := common.DecodeUint32(r)
+	vs, err := r.readVectorSize(unsafe.Sizeof(MemoryType{}))
 	if err != nil {
 		return fmt.Errorf("get size of vector: %w", err)
 	}
+	r.slabs.hint = int(vs)
+
+	if m.ImportedCount(ExternalKindMemory)+vs > 1 {
+		if err := r.require(feature.MultiMemory, "multiple memories"); err != nil {
+			return err
+		}
+	}
 
 	m.SecMemory = make([]*MemoryType, vs)
 	for i := range m.SecMemory {
 		m.SecMemory[i], err = readMemoryType(r)
 		if err != nil {
+			m.SecMemory = m.SecMemory[:i]
 			return fmt.Errorf("read %v-th memory type: %w", i, err)
 		}
 	}
 	return nil
 }
 
-func (m *Module) readSectionGlobal(r io.Reader, ss uint32) error {
+func (m *Module) readSectionGlobal(r *reader, ss uint32) error {
 	// get the vector size
-	vs, _, err := common.DecodeUint32(r)
+	vs, err := r.readVectorSize(unsafe.Sizeof(GlobalSegment{}))
 	if err != nil {
 		return fmt.Errorf("get size of vector: %w", err)
 	}
+	r.slabs.hint = int(vs)
 
 	m.SecGlobal = make([]*GlobalSegment, vs)
 	for i := range m.SecGlobal {
 		m.SecGlobal[i], err = readGlobalSegment(r)
 		if err != nil {
+			m.SecGlobal = m.SecGlobal[:i]
 			return fmt.Errorf("read %v-th global segment: %w ", i, err)
 		}
 	}
 	return nil
 }
 
-func (m *Module) readSectionExport(r io.Reader, ss uint32) error {
+func (m *Module) readSectionExport(r *reader, ss uint32) error {
 	// get the vector size
-	vs, _, err := common.DecodeUint32(r)
+	vs, err := r.readVectorSize(unsafe.Sizeof(ExportSegment{}))
 	if err != nil {
 		return fmt.Errorf("get size of vector: %w", err)
 	}
+	r.slabs.hint = int(vs)
 
 	m.SecExport = make([]*ExportSegment, vs)
 	for i := range m.SecExport {
 		m.SecExport[i], err = readExportSegment(r)
 		if err != nil {
+			m.SecExport = m.SecExport[:i]
 			return fmt.Errorf("read %v-th export segment: %w ", i, err)
 		}
+
+		if desc := m.SecExport[i].Desc; desc.Kind == ExternalKindGlobal {
+			if gt, err := m.globalType(desc.Index); err == nil && gt.Mutable {
+				if err := r.require(feature.MutableGlobals, "export of mutable global"); err != nil {
+					m.SecExport = m.SecExport[:i+1]
+					return err
+				}
+			}
+		}
 	}
 	return nil
 }
 
-func (m *Module) readSectionStart(r io.Reader, ss uint32) error {
-	idx, _, err := common.DecodeUint32(r)
+func (m *Module) readSectionStart(r *reader, ss uint32) error {
+	idx, err := r.readUint32()
 	if err != nil {
 		return fmt.Errorf("get funcIdx of start section: %w", err)
 	}
 
-	m.SecStart = idx
+	m.SecStart = &idx
 	return nil
 }
 
-func (m *Module) readSectionElement(r io.Reader, ss uint32) error {
+func (m *Module) readSectionElement(r *reader, ss uint32) error {
 	// get the vector size
-	vs, _, err := common.DecodeUint32(r)
+	vs, err := r.readVectorSize(unsafe.Sizeof(ElementSegment{}))
 	if err != nil {
 		return fmt.Errorf("get size of vector: %w", err)
 	}
+	r.slabs.hint = int(vs)
 
 	m.SecElement = make([]*ElementSegment, vs)
 	for i := range m.SecElement {
 		m.SecElement[i], err = readElementSegment(r)
 		if err != nil {
+			m.SecElement = m.SecElement[:i]
 			return fmt.Errorf("read %v-th element segment: %w ", i, err)
 		}
 	}
 	return nil
 }
 
-func (m *Module) readSectionCode(r io.Reader, ss uint32) error {
+func (m *Module) readSectionCode(r *reader, ss uint32) error {
+	sectionEnd := r.off + int64(ss)
+
 	// get the vector size
-	vs, _, err := common.DecodeUint32(r)
+	vs, err := r.readVectorSize(unsafe.Sizeof(CodeSegment{}))
 	if err != nil {
 		return fmt.Errorf("get size of vector: %w", err)
 	}
+	r.slabs.hint = int(vs)
+
+	if max := r.opts.MaxFunctions; max != 0 && uint64(m.importedFuncCount())+uint64(vs) > uint64(max) {
+		return fmt.Errorf("%w: %d imported and %d bodies > %d", common.ErrTooManyFunctions, m.importedFuncCount(), vs, max)
+	}
 
 	m.SecCode = make([]*CodeSegment, vs)
 	for i := range m.SecCode {
-		m.SecCode[i], err = readCodeSegment(r)
+		if err := r.checkContext(); err != nil {
+			m.SecCode = m.SecCode[:i]
+			return err
+		}
+
+		cs, err := r.readUint32()
 		if err != nil {
-			return fmt.Errorf("read %v-th code segment: %w ", i, err)
+			m.SecCode = m.SecCode[:i]
+			return fmt.Errorf("get the size of %v-th code segment: %w", i, err)
 		}
+
+		depth, end := r.capDepth, r.off+int64(cs)
+		m.SecCode[i], err = readCodeSegment(r, cs)
+		if err != nil {
+			err = fmt.Errorf("read %v-th code segment: %w ", i, err)
+			if !r.opts.Recover || end > sectionEnd {
+				m.SecCode = m.SecCode[:i]
+				return err
+			}
+
+			// skip the segment by its size
+			m.Diagnostics = append(m.Diagnostics, &Diagnostic{Section: SectionIDCode, Offset: r.off, Err: err})
+			r.capDepth = depth
+			if err := r.seek(end); err != nil {
+				m.SecCode = m.SecCode[:i]
+				return fmt.Errorf("skip %v-th code segment: %w", i, err)
+			}
+		}
+
+		if w := r.visitor(m); w != nil && m.SecCode[i] != nil {
+			if err := w.function(i); err != nil {
+				m.SecCode = m.SecCode[:i]
+				return fmt.Errorf("visit %v-th code segment: %w", i, err)
+			}
+			m.SecCode[i] = nil
+		}
+		r.progress(SectionIDCode)
 	}
 	return nil
 }
 
-func (m *Module) readSectionData(r io.Reader, ss uint32) error {
+func (m *Module) readSectionData(r *reader, ss uint32) error {
 	// get the vector size
-	vs, _, err := common.DecodeUint32(r)
+	vs, err := r.readVectorSize(unsafe.Sizeof(DataSegment{}))
 	if err != nil {
 		return fmt.Errorf("get size of vector: %w", err)
 	}
+	r.slabs.hint = int(vs)
 
 	m.SecData = make([]*DataSegment, vs)
 	for i := range m.SecData {
 		m.SecData[i], err = readDataSegment(r)
 		if err != nil {
+			m.SecData = m.SecData[:i]
 			return fmt.Errorf("read %v-th data segment: %w ", i, err)
 		}
 	}
 	return nil
 }
+
+func (m *Module) readSectionDataCount(r *reader, ss uint32) error {
+	if err := r.require(feature.BulkMemory, "data count section"); err != nil {
+		return err
+	}
+
+	dc, err := r.readUint32()
+	if err != nil {
+		return fmt.Errorf("get data count: %w", err)
+	}
+
+	m.SecDataCount = &dc
+	return nil
+}
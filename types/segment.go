@@ -3,45 +3,108 @@ package types
 import (
 	"fmt"
 	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/feature"
 	"github.com/LBruyne/wasm-decode/operator"
 	"io"
-	"io/ioutil"
+	"unsafe"
 )
 
+// ExternalKind is the kind of an imported or exported component
+type ExternalKind byte
+
+const (
+	ExternalKindFunc   ExternalKind = 0
+	ExternalKindTable  ExternalKind = 1
+	ExternalKindMemory ExternalKind = 2
+	ExternalKindGlobal ExternalKind = 3
+
+	ImportTypeFunc   = ExternalKindFunc
+	ImportTypeTable  = ExternalKindTable
+	ImportTypeMem    = ExternalKindMemory
+	ImportTypeGlobal = ExternalKindGlobal
+
+	ExportTypeFunc   = ExternalKindFunc
+	ExportTypeTable  = ExternalKindTable
+	ExportTypeMem    = ExternalKindMemory
+	ExportTypeGlobal = ExternalKindGlobal
+)
+
+func (k ExternalKind) String() string {
+	switch k {
+	case ExternalKindFunc:
+		return "func"
+	case ExternalKindTable:
+		return "table"
+	case ExternalKindMemory:
+		return "memory"
+	case ExternalKindGlobal:
+		return "global"
+	default:
+		return fmt.Sprintf("external(%#x)", byte(k))
+	}
+}
+
+// SegmentMode is the mode of element and data segments, segments other than active
+// ones are defined by bulk memory and reference types proposals
+type SegmentMode byte
+
 const (
-	ImportTypeFunc   = 0
-	ImportTypeTable  = 1
-	ImportTypeMem    = 2
-	ImportTypeGlobal = 3
-
-	ExportTypeFunc   = 0
-	ExportTypeTable  = 1
-	ExportTypeMem    = 2
-	ExportTypeGlobal = 3
+	SegmentModeActive      SegmentMode = 0 // copied into a table or memory at instantiation
+	SegmentModePassive     SegmentMode = 1 // copied on demand by instructions
+	SegmentModeDeclarative SegmentMode = 2 // only declares references, for element segments
 )
 
-type ImportDescription struct {
-	Kind byte // represent what type is imported
-	// possible value 0,1,2,3
+// External describes what is imported, it is one of *FuncImport, *TableImport, *MemoryImport and *GlobalImport
+type External interface {
+	Kind() ExternalKind
+	isExternal()
+}
+
+// FuncImport is an imported function of the type at TypeIndex
+type FuncImport struct {
+	TypeIndex uint32
+}
+
+// TableImport is an imported table
+type TableImport struct {
+	Type *TableType
+}
 
-	TypeIndex  uint32
-	TableType  *TableType
-	MemType    *MemoryType
-	GlobalType *GlobalType
+// MemoryImport is an imported memory
+type MemoryImport struct {
+	Type *MemoryType
 }
 
+// GlobalImport is an imported global
+type GlobalImport struct {
+	Type *GlobalType
+}
+
+func (*FuncImport) Kind() ExternalKind   { return ExternalKindFunc }
+func (*TableImport) Kind() ExternalKind  { return ExternalKindTable }
+func (*MemoryImport) Kind() ExternalKind { return ExternalKindMemory }
+func (*GlobalImport) Kind() ExternalKind { return ExternalKindGlobal }
+
+func (*FuncImport) isExternal()   {}
+func (*TableImport) isExternal()  {}
+func (*MemoryImport) isExternal() {}
+func (*GlobalImport) isExternal() {}
+
 type ImportSegment struct {
 	Name, Module string
-	Desc         *ImportDescription
+	Desc         External
+
+	// InvalidName is set if Name or Module is not valid UTF-8, only if DecodeOptions.AllowInvalidUTF8 is set
+	InvalidName bool
 }
 
-func readImportSegment(r io.Reader) (*ImportSegment, error) {
-	mn, err := ReadString(r)
+func readImportSegment(r *reader) (*ImportSegment, error) {
+	mn, mValid, err := r.readName()
 	if err != nil {
 		return nil, fmt.Errorf("read module name of imported component: %w", err)
 	}
 
-	n, err := ReadString(r)
+	n, nValid, err := r.readName()
 	if err != nil {
 		return nil, fmt.Errorf("read name of imported component: %w", err)
 	}
@@ -51,49 +114,56 @@ func readImportSegment(r io.Reader) (*ImportSegment, error) {
 		return nil, fmt.Errorf("read description of imported component: %w", err)
 	}
 
-	return &ImportSegment{
-		Module: mn,
-		Name:   n,
-		Desc:   desc,
-	}, nil
+	ret := r.slabs.newImportSegment()
+	*ret = ImportSegment{
+		Module:      mn,
+		Name:        n,
+		Desc:        desc,
+		InvalidName: !mValid || !nValid,
+	}
+	return ret, nil
 }
 
-func readImportDescription(r io.Reader) (*ImportDescription, error) {
-	k, err := ReadByte(r)
+func readImportDescription(r *reader) (External, error) {
+	k, err := r.ReadByte()
 	if err != nil {
 		return nil, fmt.Errorf("read kind of import description: %w", err)
 	}
 
-	ret := &ImportDescription{
-		Kind: k,
-	}
-
-	switch k {
-	case ImportTypeFunc:
-		ret.TypeIndex, _, err = common.DecodeUint32(r)
+	switch ExternalKind(k) {
+	case ExternalKindFunc:
+		ret := r.slabs.newFuncImport()
+		ret.TypeIndex, err = r.readUint32()
 		if err != nil {
 			return nil, fmt.Errorf("read type index: %w", err)
 		}
-	case ImportTypeTable:
-		ret.TableType, err = readTableType(r)
+		return ret, nil
+	case ExternalKindTable:
+		tt, err := readTableType(r)
 		if err != nil {
 			return nil, fmt.Errorf("read table type: %w", err)
 		}
-	case ImportTypeMem:
-		ret.MemType, err = readMemoryType(r)
+		return &TableImport{Type: tt}, nil
+	case ExternalKindMemory:
+		mt, err := readMemoryType(r)
 		if err != nil {
 			return nil, fmt.Errorf("read memory type: %w", err)
 		}
-	case ImportTypeGlobal:
-		ret.GlobalType, err = readGlobalType(r)
+		return &MemoryImport{Type: mt}, nil
+	case ExternalKindGlobal:
+		gt, err := readGlobalType(r)
 		if err != nil {
 			return nil, fmt.Errorf("read global type: %w", err)
 		}
+		if gt.Mutable {
+			if err := r.require(feature.MutableGlobals, "import of mutable global"); err != nil {
+				return nil, err
+			}
+		}
+		return &GlobalImport{Type: gt}, nil
 	default:
 		return nil, fmt.Errorf("invalid kind of import description: %v", k)
 	}
-
-	return ret, nil
 }
 
 type GlobalSegment struct {
@@ -101,7 +171,7 @@ type GlobalSegment struct {
 	Init *InitExpression
 }
 
-func readGlobalSegment(r io.Reader) (*GlobalSegment, error) {
+func readGlobalSegment(r *reader) (*GlobalSegment, error) {
 	gt, err := readGlobalType(r)
 	if err != nil {
 		return nil, fmt.Errorf("read global type: %w", err)
@@ -112,109 +182,236 @@ func readGlobalSegment(r io.Reader) (*GlobalSegment, error) {
 		return nil, fmt.Errorf("read expression: %w", err)
 	}
 
-	return &GlobalSegment{
+	ret := r.slabs.newGlobalSegment()
+	*ret = GlobalSegment{
 		Type: gt,
 		Init: init,
-	}, nil
+	}
+	return ret, nil
 }
 
 type DataSegment struct {
+	Mode   SegmentMode
 	MemIdx uint32
-	Offset *OffsetExpression
+	Offset *OffsetExpression // nil for passive segment
 	Init   []byte
+
+	InitOffset int64  // offset of Init in the module binary
+	InitSize   uint32 // size of Init in bytes
+
+	src io.ReaderAt // where to load Init from if it is not read yet
 }
 
-func readDataSegment(r io.Reader) (*DataSegment, error) {
-	mi, _, err := common.DecodeUint32(r)
-	if err != nil {
-		return nil, fmt.Errorf("get memory index: %w", err)
+// LoadInit returns Init of the data segment, which is read from the underlying io.ReaderAt
+// at the first call if the module is decoded lazily.
+func (d *DataSegment) LoadInit() ([]byte, error) {
+	if d.src == nil {
+		return d.Init, nil
 	}
 
-	// TODO WASM 1.0 defines that memory index must be 0
-	if mi != 0 {
-		return nil, fmt.Errorf("invalid memory index: %d, must be 0 which is defined by WASM 1.0", mi)
+	init, err := loadAt(d.src, d.InitOffset, int64(d.InitSize))
+	if err != nil {
+		return nil, fmt.Errorf("load init at %d: %w", d.InitOffset, err)
 	}
 
-	expr, err := readOffsetExpression(r)
+	d.Init, d.src = init, nil
+	return d.Init, nil
+}
+
+func readDataSegment(r *reader) (*DataSegment, error) {
+	// WASM 1.0 defines a memory index which must be 0,
+	// bulk memory proposal turns it into a flag of the segment mode
+	flag, err := r.readUint32()
 	if err != nil {
-		return nil, fmt.Errorf("read expr for offset: %w", err)
+		return nil, fmt.Errorf("get flag of data segment: %w", err)
+	}
+
+	if flag > 2 {
+		return nil, fmt.Errorf("invalid flag of data segment: %d", flag)
+	}
+	if flag != 0 {
+		if err := r.require(feature.BulkMemory, fmt.Sprintf("data segment of flag %d", flag)); err != nil {
+			return nil, err
+		}
+	}
+
+	ret := r.slabs.newDataSegment()
+	switch flag {
+	case 1:
+		ret.Mode = SegmentModePassive
+	case 2:
+		ret.MemIdx, err = r.readUint32()
+		if err != nil {
+			return nil, fmt.Errorf("get memory index: %w", err)
+		}
+		if ret.MemIdx != 0 {
+			if err := r.require(feature.MultiMemory, "data segment of non-zero memory index"); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	if expr.OpCode != operator.OpCodeI32Const {
-		return nil, fmt.Errorf("offset expression must be i32.const but get %#x", expr.OpCode)
+	if ret.Mode == SegmentModeActive {
+		ret.Offset, err = readOffsetExpression(r)
+		if err != nil {
+			return nil, fmt.Errorf("read expr for offset: %w", err)
+		}
+
+		if ret.Offset.OpCode != operator.OpCodeI32Const {
+			return nil, fmt.Errorf("offset expression must be i32.const but get %v", ret.Offset.OpCode)
+		}
 	}
 
-	vs, _, err := common.DecodeUint32(r)
+	vs, err := r.readUint32()
 	if err != nil {
 		return nil, fmt.Errorf("get size of vector: %w", err)
 	}
 
-	init := make([]byte, vs)
-	if _, err := io.ReadFull(r, init); err != nil {
-		return nil, fmt.Errorf("read bytes for init: %w", err)
+	ret.InitOffset = r.off
+	ret.InitSize = vs
+
+	if r.lazy() {
+		if err := r.skip(vs); err != nil {
+			return nil, fmt.Errorf("skip bytes for init: %w", err)
+		}
+		ret.src = r.ra
+		return ret, nil
 	}
 
-	return &DataSegment{
-		MemIdx: mi,
-		Offset: expr,
-		Init:   init,
-	}, nil
+	ret.Init, err = r.readBytes(vs)
+	if err != nil {
+		return nil, fmt.Errorf("read bytes for init: %w", err)
+	}
+	return ret, nil
 }
 
 type ElementSegment struct {
+	Mode     SegmentMode
 	TableIdx uint32
-	Offset   *OffsetExpression
-	Init     []uint32 // function index
+	Offset   *OffsetExpression // nil for passive and declarative segments
+	ElemType ValueType
+	Init     []uint32           // function index
+	Exprs    []*ConstExpression // element expressions, used instead of Init by reference types proposal
 }
 
-func readElementSegment(r io.Reader) (*ElementSegment, error) {
-	ti, _, err := common.DecodeUint32(r)
+func readElementSegment(r *reader) (*ElementSegment, error) {
+	// WASM 1.0 defines a table index which must be 0, bulk memory and reference types proposals
+	// turn it into flags: bit 0 for passive or declarative, bit 1 for explicit table index
+	// or declarative, bit 2 for element expressions
+	flag, err := r.readUint32()
 	if err != nil {
-		return nil, fmt.Errorf("get table index: %w", err)
+		return nil, fmt.Errorf("get flag of element segment: %w", err)
 	}
 
-	expr, err := readOffsetExpression(r)
-	if err != nil {
-		return nil, fmt.Errorf("read expr for offset: %w", err)
+	if flag > 7 {
+		return nil, fmt.Errorf("invalid flag of element segment: %d", flag)
+	}
+	if flag != 0 {
+		if err := r.require(feature.BulkMemory, fmt.Sprintf("element segment of flag %d", flag)); err != nil {
+			return nil, err
+		}
+	}
+	if flag == 3 || flag&4 != 0 {
+		if err := r.require(feature.ReferenceTypes, fmt.Sprintf("element segment of flag %d", flag)); err != nil {
+			return nil, err
+		}
+	}
+
+	ret := &ElementSegment{
+		ElemType: ElemTypeFuncRef,
+	}
+	switch {
+	case flag&1 == 0:
+		if flag&2 != 0 {
+			ret.TableIdx, err = r.readUint32()
+			if err != nil {
+				return nil, fmt.Errorf("get table index: %w", err)
+			}
+			if ret.TableIdx != 0 {
+				if err := r.require(feature.ReferenceTypes, "element segment of non-zero table index"); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		ret.Offset, err = readOffsetExpression(r)
+		if err != nil {
+			return nil, fmt.Errorf("read expr for offset: %w", err)
+		}
+
+		if ret.Offset.OpCode != operator.OpCodeI32Const {
+			return nil, fmt.Errorf("offset expression must be i32.const but get %v", ret.Offset.OpCode)
+		}
+	case flag&2 == 0:
+		ret.Mode = SegmentModePassive
+	default:
+		ret.Mode = SegmentModeDeclarative
 	}
 
-	if expr.OpCode != operator.OpCodeI32Const {
-		return nil, fmt.Errorf("offset expression must be i32.const but get %#x", expr.OpCode)
+	// element kind or reference type is omitted by flag 0 and 4
+	if flag&3 != 0 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("read element type: %w", err)
+		}
+
+		switch et := ValueType(b); {
+		case flag&4 == 0 && b == 0x00:
+			// element kind 0x00 stands for funcref
+			ret.ElemType = ElemTypeFuncRef
+		case flag&4 != 0 && et.IsReference():
+			ret.ElemType = et
+		default:
+			return nil, fmt.Errorf("invalid byte for element type: %#x", b)
+		}
 	}
 
-	vs, _, err := common.DecodeUint32(r)
+	if flag&4 != 0 {
+		vs, err := r.readVectorSize(unsafe.Sizeof(ConstExpression{}))
+		if err != nil {
+			return nil, fmt.Errorf("get size of vector: %w", err)
+		}
+
+		ret.Exprs = make([]*ConstExpression, vs)
+		for i := range ret.Exprs {
+			ret.Exprs[i], err = readConstExpression(r)
+			if err != nil {
+				return nil, fmt.Errorf("read %v-th element expression: %w", i, err)
+			}
+		}
+		return ret, nil
+	}
+
+	vs, err := r.readVectorSize(unsafe.Sizeof(uint32(0)))
 	if err != nil {
 		return nil, fmt.Errorf("get size of vector: %w", err)
 	}
 
-	init := make([]uint32, vs)
-	for i := range init {
-		fi, _, err := common.DecodeUint32(r)
+	ret.Init = make([]uint32, vs)
+	for i := range ret.Init {
+		ret.Init[i], err = r.readUint32()
 		if err != nil {
 			return nil, fmt.Errorf("read %v-th function index: %w", i, err)
 		}
-		init[i] = fi
 	}
-
-	return &ElementSegment{
-		TableIdx: ti,
-		Offset:   expr,
-		Init:     init,
-	}, nil
+	return ret, nil
 }
 
 type ExportSegment struct {
 	Name string
 	Desc *ExportDescription
+
+	// InvalidName is set if Name is not valid UTF-8, only if DecodeOptions.AllowInvalidUTF8 is set
+	InvalidName bool
 }
 
 type ExportDescription struct {
-	Kind  byte
+	Kind  ExternalKind
 	Index uint32
 }
 
-func readExportSegment(r io.Reader) (*ExportSegment, error) {
-	name, err := ReadString(r)
+func readExportSegment(r *reader) (*ExportSegment, error) {
+	name, valid, err := r.readName()
 	if err != nil {
 		return nil, fmt.Errorf("read name of export module: %w", err)
 	}
@@ -224,14 +421,17 @@ func readExportSegment(r io.Reader) (*ExportSegment, error) {
 		return nil, fmt.Errorf("read export description: %w", err)
 	}
 
-	return &ExportSegment{
-		Name: name,
-		Desc: desc,
-	}, nil
+	ret := r.slabs.newExportSegment()
+	*ret = ExportSegment{
+		Name:        name,
+		Desc:        desc,
+		InvalidName: !valid,
+	}
+	return ret, nil
 }
 
-func readExportDescription(r io.Reader) (*ExportDescription, error) {
-	k, err := ReadByte(r)
+func readExportDescription(r *reader) (*ExportDescription, error) {
+	k, err := r.ReadByte()
 	if err != nil {
 		return nil, fmt.Errorf("read kind of export description: %w", err)
 	}
@@ -242,38 +442,67 @@ func readExportDescription(r io.Reader) (*ExportDescription, error) {
 		return nil, fmt.Errorf("invalid byte for export description: %#x", k)
 	}
 
-	id, _, err := common.DecodeUint32(r)
+	id, err := r.readUint32()
 	if err != nil {
 		return nil, fmt.Errorf("read idx: %w", err)
 	}
 
-	return &ExportDescription{
-		Kind:  k,
+	ret := r.slabs.newExportDescription()
+	*ret = ExportDescription{
+		Kind:  ExternalKind(k),
 		Index: id,
-	}, nil
+	}
+	return ret, nil
 }
 
 type CodeSegment struct {
 	Locals    []*LocalValueType
 	NumLocals uint32
 	Body      CodeSegmentBody
+
+	BodyOffset int64  // offset of Body in the module binary
+	BodySize   uint32 // size of Body in bytes
+
+	src      io.ReaderAt // where to load Body from if it is not read yet
+	features feature.Set // enabled features the instructions are checked against once Body is loaded
 }
 
-func readCodeSegment(r io.Reader) (*CodeSegment, error) {
-	ss, _, err := common.DecodeUint32(r)
+// LoadBody returns Body of the code segment, which is read from the underlying io.ReaderAt
+// at the first call if the module is decoded lazily.
+func (c *CodeSegment) LoadBody() (CodeSegmentBody, error) {
+	if c.src == nil {
+		return c.Body, nil
+	}
+
+	cb, err := loadAt(c.src, c.BodyOffset, int64(c.BodySize))
 	if err != nil {
-		return nil, fmt.Errorf("get the size of code segment: %w", err)
+		return nil, fmt.Errorf("load code body at %d: %w", c.BodyOffset, err)
+	}
+	if operator.OpCode(cb[len(cb)-1]) != operator.OpCodeEnd {
+		return nil, fmt.Errorf("load code body: invalid end OpCode")
+	}
+	if err := checkBody(c.features, cb, c.BodyOffset); err != nil {
+		return nil, fmt.Errorf("load code body: %w", err)
 	}
 
-	r = io.LimitReader(r, int64(ss))
+	c.Body, c.src = cb, nil
+	return c.Body, nil
+}
+
+// readCodeSegment read a code segment of ss bytes following its size
+func readCodeSegment(r *reader, ss uint32) (*CodeSegment, error) {
+	if max := r.opts.MaxFunctionSize; max != 0 && ss > max {
+		return nil, fmt.Errorf("%w: %d > %d bytes", common.ErrFunctionTooLarge, ss, max)
+	}
+	start := r.off
 
 	// parse locals
-	ls, _, err := common.DecodeUint32(r)
+	ls, err := r.readVectorSize(unsafe.Sizeof(LocalValueType{}))
 	if err != nil {
 		return nil, fmt.Errorf("get the size locals: %w", err)
 	}
 
-	locals := make([]*LocalValueType, ls)
+	locals := r.slabs.newLocalPtrs(ls)
 	for i := range locals {
 		l, err := readLocalValueType(r)
 		if err != nil {
@@ -282,18 +511,73 @@ func readCodeSegment(r io.Reader) (*CodeSegment, error) {
 		locals[i] = l
 	}
 
+	// the body takes up the rest of the segment, at least the end OpCode
+	if r.off-start >= int64(ss) {
+		return nil, fmt.Errorf("read code body: locals exceed the size of code segment %d", ss)
+	}
+
+	nl, err := getNumLocals(locals, r.opts.MaxLocals)
+	if err != nil {
+		return nil, fmt.Errorf("count locals: %w", err)
+	}
+
+	ret := r.slabs.newCodeSegment()
+	*ret = CodeSegment{
+		Locals:     locals,
+		NumLocals:  nl,
+		BodyOffset: r.off,
+		BodySize:   ss - uint32(r.off-start),
+	}
+
 	// parse code body
-	cb, err := ioutil.ReadAll(r)
+	if r.lazy() {
+		if err := r.skip(ret.BodySize); err != nil {
+			return nil, fmt.Errorf("skip code body: %w", err)
+		}
+		ret.src, ret.features = r.ra, r.opts.Features
+		return ret, nil
+	}
+
+	cb, err := r.readBytes(ret.BodySize)
 	if err != nil {
 		return nil, fmt.Errorf("read code body: %w", err)
 	}
 	if operator.OpCode(cb[len(cb)-1]) != operator.OpCodeEnd {
 		return nil, fmt.Errorf("read code body: invalid end OpCode")
 	}
+	if err := checkBody(r.opts.Features, cb, ret.BodyOffset); err != nil {
+		return nil, fmt.Errorf("read code body: %w", err)
+	}
 
-	return &CodeSegment{
-		Body:      cb,
-		Locals:    locals,
-		NumLocals: getNumLocals(locals),
-	}, nil
+	ret.Body = cb
+	return ret, nil
+}
+
+// bodyFeatures are the features instructions may require, the bodies need no check if they are all enabled
+var bodyFeatures = func() feature.Set {
+	s := feature.MVP.With(feature.MultiValue, feature.ReferenceTypes)
+	for _, info := range operator.Infos() {
+		s = s.With(info.Feature)
+	}
+	return s
+}()
+
+// checkBody returns an error if an instruction of body, found at offset, requires a feature not in features
+func checkBody(features feature.Set, body []byte, offset int64) error {
+	if features&bodyFeatures == bodyFeatures {
+		return nil
+	}
+
+	var ins operator.Instruction
+	for off := 0; off < len(body); {
+		n, err := operator.DecodeInstruction(body[off:], &ins)
+		if err != nil {
+			return fmt.Errorf("read instruction at %#x: %w", offset+int64(off), err)
+		}
+		if f, ok := instructionFeature(&ins); ok && !features.Has(f) {
+			return fmt.Errorf("%w: %s at %#x requires %s", common.ErrFeatureDisabled, describeInstruction(&ins), offset+int64(off), f)
+		}
+		off += n
+	}
+	return nil
 }
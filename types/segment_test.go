@@ -0,0 +1,47 @@
+package types_test
+
+import (
+	"github.com/LBruyne/wasm-decode/builder"
+	"github.com/LBruyne/wasm-decode/decode"
+	"github.com/LBruyne/wasm-decode/operator"
+	"github.com/LBruyne/wasm-decode/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// externalsModule imports a function and a global, and exports and starts its own function
+func externalsModule(t *testing.T) []byte {
+	b := builder.New()
+	sig := b.AddType(nil, nil)
+	b.ImportFunc("env", "f", sig)
+	b.ImportGlobal("env", "g", types.ValueTypeI32, false)
+	f := b.AddFunction(sig, nil, []byte{byte(operator.OpCodeEnd)})
+	bs, err := b.ExportFunc("f", f).SetStart(f).Bytes()
+	assert.Nil(t, err)
+	return bs
+}
+
+func TestExternals(t *testing.T) {
+	mod, err := decode.DecodeBytes(externalsModule(t))
+	assert.Nil(t, err)
+	if assert.Len(t, mod.SecImport, 2) {
+		assert.Equal(t, &types.FuncImport{TypeIndex: 0}, mod.SecImport[0].Desc)
+		assert.Equal(t, types.ExternalKindFunc, mod.SecImport[0].Desc.Kind())
+		assert.Equal(t, &types.GlobalImport{Type: &types.GlobalType{Value: types.ValueTypeI32}}, mod.SecImport[1].Desc)
+		assert.Equal(t, types.ExternalKindGlobal, mod.SecImport[1].Desc.Kind())
+	}
+	if assert.Len(t, mod.SecExport, 1) {
+		assert.Equal(t, &types.ExportDescription{Kind: types.ExternalKindFunc, Index: 1}, mod.SecExport[0].Desc)
+	}
+	if assert.NotNil(t, mod.SecStart) {
+		assert.Equal(t, uint32(1), *mod.SecStart)
+	}
+
+	mod, err = decode.DecodeFile(exampleFile)
+	assert.Nil(t, err)
+	assert.Nil(t, mod.SecStart)
+
+	assert.Equal(t, "func", types.ExternalKindFunc.String())
+	assert.Equal(t, "memory", types.ExternalKindMemory.String())
+	assert.Equal(t, "external(0x4)", types.ExternalKind(4).String())
+}
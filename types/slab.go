@@ -0,0 +1,211 @@
+package types
+
+const (
+	// maxSlab is the maximum number of items of a chunk, unless one vector needs more
+	maxSlab = 1024
+	// maxByteSlab is the maximum size of a chunk of bytes, unless one payload needs more
+	maxByteSlab = 1 << 20
+	// sectionSlab is the number of sections of a chunk, which holds the sections of most modules
+	sectionSlab = 16
+)
+
+// slabs hands out the items of a module from chunks allocated at once, instead of one by one.
+// Each slice keeps its chunk as capacity and the items handed out as length.
+// Chunks are sized by hint, the number of items of the section being read,
+// so that a small module does not allocate more than it needs.
+type slabs struct {
+	hint     int
+	byteHint int // size of the section being read, which bounds its payloads
+	payloads []byte
+
+	sections    []SectionInfo
+	customs     []CustomSec
+	funcTypes   []FunctionType
+	valueTypes  []ValueType
+	imports     []ImportSegment
+	funcImports []FuncImport
+	exports     []ExportSegment
+	exportDescs []ExportDescription
+	globals     []GlobalSegment
+	globalTypes []GlobalType
+	exprs       []ConstExpression
+	limits      []LimitType
+	codes       []CodeSegment
+	locals      []LocalValueType
+	localPtrs   []*LocalValueType
+	datas       []DataSegment
+}
+
+// chunkSize returns the capacity of a new chunk, which holds at least n items
+// and perItem items for each item of the section
+func (s *slabs) chunkSize(n, perItem int) int {
+	c := s.hint * perItem
+	if c > maxSlab {
+		c = maxSlab
+	}
+	if c < n {
+		c = n
+	}
+	return c
+}
+
+func (s *slabs) newSectionInfo() *SectionInfo {
+	if len(s.sections) == cap(s.sections) {
+		s.sections = make([]SectionInfo, 0, sectionSlab)
+	}
+	s.sections = s.sections[:len(s.sections)+1]
+	return &s.sections[len(s.sections)-1]
+}
+
+func (s *slabs) newCustomSec() *CustomSec {
+	if len(s.customs) == cap(s.customs) {
+		s.customs = make([]CustomSec, 0, sectionSlab/4)
+	}
+	s.customs = s.customs[:len(s.customs)+1]
+	return &s.customs[len(s.customs)-1]
+}
+
+func (s *slabs) newFunctionType() *FunctionType {
+	if len(s.funcTypes) == cap(s.funcTypes) {
+		s.funcTypes = make([]FunctionType, 0, s.chunkSize(1, 1))
+	}
+	s.funcTypes = s.funcTypes[:len(s.funcTypes)+1]
+	return &s.funcTypes[len(s.funcTypes)-1]
+}
+
+// newValueTypes returns a slice of n value types, whose capacity is n so that appending to it
+// never overwrites the following items
+func (s *slabs) newValueTypes(n uint32) []ValueType {
+	if n == 0 {
+		return []ValueType{}
+	}
+
+	l := len(s.valueTypes)
+	if int(n) > cap(s.valueTypes)-l {
+		s.valueTypes = make([]ValueType, 0, s.chunkSize(int(n), 4))
+		l = 0
+	}
+	s.valueTypes = s.valueTypes[:l+int(n)]
+	return s.valueTypes[l : l+int(n) : l+int(n)]
+}
+
+func (s *slabs) newImportSegment() *ImportSegment {
+	if len(s.imports) == cap(s.imports) {
+		s.imports = make([]ImportSegment, 0, s.chunkSize(1, 1))
+	}
+	s.imports = s.imports[:len(s.imports)+1]
+	return &s.imports[len(s.imports)-1]
+}
+
+func (s *slabs) newFuncImport() *FuncImport {
+	if len(s.funcImports) == cap(s.funcImports) {
+		s.funcImports = make([]FuncImport, 0, s.chunkSize(1, 1))
+	}
+	s.funcImports = s.funcImports[:len(s.funcImports)+1]
+	return &s.funcImports[len(s.funcImports)-1]
+}
+
+func (s *slabs) newExportSegment() *ExportSegment {
+	if len(s.exports) == cap(s.exports) {
+		s.exports = make([]ExportSegment, 0, s.chunkSize(1, 1))
+	}
+	s.exports = s.exports[:len(s.exports)+1]
+	return &s.exports[len(s.exports)-1]
+}
+
+func (s *slabs) newExportDescription() *ExportDescription {
+	if len(s.exportDescs) == cap(s.exportDescs) {
+		s.exportDescs = make([]ExportDescription, 0, s.chunkSize(1, 1))
+	}
+	s.exportDescs = s.exportDescs[:len(s.exportDescs)+1]
+	return &s.exportDescs[len(s.exportDescs)-1]
+}
+
+func (s *slabs) newGlobalSegment() *GlobalSegment {
+	if len(s.globals) == cap(s.globals) {
+		s.globals = make([]GlobalSegment, 0, s.chunkSize(1, 1))
+	}
+	s.globals = s.globals[:len(s.globals)+1]
+	return &s.globals[len(s.globals)-1]
+}
+
+func (s *slabs) newGlobalType() *GlobalType {
+	if len(s.globalTypes) == cap(s.globalTypes) {
+		s.globalTypes = make([]GlobalType, 0, s.chunkSize(1, 1))
+	}
+	s.globalTypes = s.globalTypes[:len(s.globalTypes)+1]
+	return &s.globalTypes[len(s.globalTypes)-1]
+}
+
+func (s *slabs) newConstExpression() *ConstExpression {
+	if len(s.exprs) == cap(s.exprs) {
+		s.exprs = make([]ConstExpression, 0, s.chunkSize(1, 1))
+	}
+	s.exprs = s.exprs[:len(s.exprs)+1]
+	return &s.exprs[len(s.exprs)-1]
+}
+
+func (s *slabs) newLimitType() *LimitType {
+	if len(s.limits) == cap(s.limits) {
+		s.limits = make([]LimitType, 0, s.chunkSize(1, 1))
+	}
+	s.limits = s.limits[:len(s.limits)+1]
+	return &s.limits[len(s.limits)-1]
+}
+
+func (s *slabs) newCodeSegment() *CodeSegment {
+	if len(s.codes) == cap(s.codes) {
+		s.codes = make([]CodeSegment, 0, s.chunkSize(1, 1))
+	}
+	s.codes = s.codes[:len(s.codes)+1]
+	return &s.codes[len(s.codes)-1]
+}
+
+func (s *slabs) newLocalValueType() *LocalValueType {
+	if len(s.locals) == cap(s.locals) {
+		s.locals = make([]LocalValueType, 0, s.chunkSize(1, 2))
+	}
+	s.locals = s.locals[:len(s.locals)+1]
+	return &s.locals[len(s.locals)-1]
+}
+
+// newLocalPtrs returns a slice of n pointers to locals, whose capacity is n
+func (s *slabs) newLocalPtrs(n uint32) []*LocalValueType {
+	if n == 0 {
+		return []*LocalValueType{}
+	}
+
+	l := len(s.localPtrs)
+	if int(n) > cap(s.localPtrs)-l {
+		s.localPtrs = make([]*LocalValueType, 0, s.chunkSize(int(n), 2))
+		l = 0
+	}
+	s.localPtrs = s.localPtrs[:l+int(n)]
+	return s.localPtrs[l : l+int(n) : l+int(n)]
+}
+
+func (s *slabs) newDataSegment() *DataSegment {
+	if len(s.datas) == cap(s.datas) {
+		s.datas = make([]DataSegment, 0, s.chunkSize(1, 1))
+	}
+	s.datas = s.datas[:len(s.datas)+1]
+	return &s.datas[len(s.datas)-1]
+}
+
+// newBytes returns n bytes for a payload copied out of the input, whose capacity is n
+func (s *slabs) newBytes(n uint32) []byte {
+	l := len(s.payloads)
+	if int(n) > cap(s.payloads)-l {
+		c := s.byteHint
+		if c > maxByteSlab {
+			c = maxByteSlab
+		}
+		if c < int(n) {
+			c = int(n)
+		}
+		s.payloads = make([]byte, 0, c)
+		l = 0
+	}
+	s.payloads = s.payloads[:l+int(n)]
+	return s.payloads[l : l+int(n) : l+int(n)]
+}
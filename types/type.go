@@ -3,64 +3,122 @@ package types
 import (
 	"fmt"
 	"github.com/LBruyne/wasm-decode/common"
-	"io"
+	"github.com/LBruyne/wasm-decode/feature"
+	"math"
+	"unsafe"
 )
 
 const (
 	// FuncType represents the function of a section type
 	FuncType byte = 0x60
 
-	ElemTypeFuncRef = 0x70
+	// ElemTypeFuncRef and ElemTypeExternRef are the reference types of tables and element segments
+	ElemTypeFuncRef   = ValueTypeFuncRef
+	ElemTypeExternRef = ValueTypeExternRef
 
 	LimitTypeOnlyMin       = 0
 	LimitTypeBothMinAndMax = 1
+	LimitTypeShared        = 0x02 // flag of shared memory, defined by threads proposal
+	LimitType64            = 0x04 // flag of 64-bit memory, defined by memory64 proposal
 
-	GlobalTypeNotMutable = 1
-	GlobalTypeMutable    = 0
+	// mutability of global types, 0x00 for const and 0x01 for var
+	GlobalTypeNotMutable = 0
+	GlobalTypeMutable    = 1
 )
 
-var (
-	ValueTypeI32 = ValueType{
-		Type:     "i32",
-		Bytecode: 0x7f,
-	}
-	ValueTypeI64 = ValueType{
-		Type:     "i64",
-		Bytecode: 0x7e,
-	}
-	ValueTypeF32 = ValueType{
-		Type:     "f32",
-		Bytecode: 0x7d,
-	}
-	ValueTypeF64 = ValueType{
-		Type:     "f64",
-		Bytecode: 0x7c,
-	}
+// ValueType is a value type, represented by its type code in the binary format.
+// The codes of reference types are the ones of their heap types, the bits above
+// the code are left for the type index of typed references.
+type ValueType uint32
+
+const (
+	ValueTypeI32       ValueType = 0x7f
+	ValueTypeI64       ValueType = 0x7e
+	ValueTypeF32       ValueType = 0x7d
+	ValueTypeF64       ValueType = 0x7c
+	ValueTypeV128      ValueType = 0x7b // defined by SIMD proposal
+	ValueTypeFuncRef   ValueType = 0x70 // defined by reference types proposal
+	ValueTypeExternRef ValueType = 0x6f // defined by reference types proposal
 )
 
-type ValueType struct {
-	Type     string
-	Bytecode byte
+// HeapType is the type of what a reference points to
+type HeapType uint32
+
+const (
+	HeapTypeFunc   HeapType = 0x70
+	HeapTypeExtern HeapType = 0x6f
+)
+
+// Code returns the type code of vt in the binary format
+func (vt ValueType) Code() byte {
+	return byte(vt)
+}
+
+// IsNumeric reports whether vt is one of i32, i64, f32 and f64
+func (vt ValueType) IsNumeric() bool {
+	return vt >= ValueTypeF64 && vt <= ValueTypeI32
+}
+
+// IsVector reports whether vt is v128
+func (vt ValueType) IsVector() bool {
+	return vt == ValueTypeV128
+}
+
+// IsReference reports whether vt is a reference type
+func (vt ValueType) IsReference() bool {
+	return vt == ValueTypeFuncRef || vt == ValueTypeExternRef
+}
+
+// HeapType returns what a reference of type vt points to, it is valid only for reference types
+func (vt ValueType) HeapType() HeapType {
+	return HeapType(vt.Code())
+}
+
+func (vt ValueType) String() string {
+	switch vt {
+	case ValueTypeI32:
+		return "i32"
+	case ValueTypeI64:
+		return "i64"
+	case ValueTypeF32:
+		return "f32"
+	case ValueTypeF64:
+		return "f64"
+	case ValueTypeV128:
+		return "v128"
+	case ValueTypeFuncRef:
+		return "funcref"
+	case ValueTypeExternRef:
+		return "externref"
+	default:
+		return fmt.Sprintf("valtype(%#x)", uint32(vt))
+	}
+}
+
+func (h HeapType) String() string {
+	switch h {
+	case HeapTypeFunc:
+		return "func"
+	case HeapTypeExtern:
+		return "extern"
+	default:
+		return fmt.Sprintf("heaptype(%#x)", uint32(h))
+	}
 }
 
 func getValueType(bc byte) (ValueType, error) {
-	switch bc {
-	case ValueTypeF64.Bytecode:
-		return ValueTypeF64, nil
-	case ValueTypeF32.Bytecode:
-		return ValueTypeF32, nil
-	case ValueTypeI64.Bytecode:
-		return ValueTypeI64, nil
-	case ValueTypeI32.Bytecode:
-		return ValueTypeI32, nil
+	switch vt := ValueType(bc); vt {
+	case ValueTypeI32, ValueTypeI64, ValueTypeF32, ValueTypeF64,
+		ValueTypeV128, ValueTypeFuncRef, ValueTypeExternRef:
+		return vt, nil
 	default:
-		return ValueType{}, fmt.Errorf("invalid value type: %v", bc)
+		return 0, fmt.Errorf("invalid value type: %#x", bc)
 	}
 }
 
 // readValueTypes read s ValueTypes from r
-func readValueTypes(r io.Reader, s uint32) ([]ValueType, error) {
-	ret := make([]ValueType, s)
+func readValueTypes(r *reader, s uint32) ([]ValueType, error) {
+	ret := r.slabs.newValueTypes(s)
 	for i := range ret {
 		vt, err := readValueType(r)
 		if err != nil {
@@ -72,15 +130,25 @@ func readValueTypes(r io.Reader, s uint32) ([]ValueType, error) {
 }
 
 // readValueType read a ValueType from r
-func readValueType(r io.Reader) (ValueType, error) {
-	b, err := ReadByte(r)
+func readValueType(r *reader) (ValueType, error) {
+	b, err := r.ReadByte()
 	if err != nil {
-		return ValueType{}, err
+		return 0, err
 	}
 
 	vt, err := getValueType(b)
 	if err != nil {
-		return ValueType{}, err
+		return 0, err
+	}
+
+	switch vt {
+	case ValueTypeV128:
+		err = r.require(feature.SIMD, "value type v128")
+	case ValueTypeFuncRef, ValueTypeExternRef:
+		err = r.require(feature.ReferenceTypes, "value type "+vt.String())
+	}
+	if err != nil {
+		return 0, err
 	}
 	return vt, nil
 }
@@ -90,19 +158,19 @@ type FunctionType struct {
 }
 
 // readValueTypes read a FunctionType from r
-func readFunctionType(r io.Reader) (*FunctionType, error) {
+func readFunctionType(r *reader) (*FunctionType, error) {
 	// first read a byte `0x60`
-	b := make([]byte, 1)
-	if _, err := io.ReadFull(r, b); err != nil {
+	b, err := r.ReadByte()
+	if err != nil {
 		return nil, fmt.Errorf("read leading byte: %w", err)
 	}
 
-	if b[0] != FuncType {
-		return nil, fmt.Errorf("%w: %#x != 0x60", common.ErrInvalidByte, b[0])
+	if b != FuncType {
+		return nil, fmt.Errorf("%w: %#x != 0x60", common.ErrInvalidByte, b)
 	}
 
 	// read inputs
-	is, _, err := common.DecodeUint32(r)
+	is, err := r.readVectorSize(unsafe.Sizeof(ValueType(0)))
 	if err != nil {
 		return nil, fmt.Errorf("get the size of input value types: %w", err)
 	}
@@ -113,7 +181,7 @@ func readFunctionType(r io.Reader) (*FunctionType, error) {
 	}
 
 	// read outputs
-	os, _, err := common.DecodeUint32(r)
+	os, err := r.readVectorSize(unsafe.Sizeof(ValueType(0)))
 	if err != nil {
 		return nil, fmt.Errorf("get the size of output value types: %w", err)
 	}
@@ -123,26 +191,39 @@ func readFunctionType(r io.Reader) (*FunctionType, error) {
 		return nil, fmt.Errorf("read value types of outputs: %w", err)
 	}
 
-	return &FunctionType{
+	if len(out) > 1 {
+		if err := r.require(feature.MultiValue, "function type with multiple results"); err != nil {
+			return nil, err
+		}
+	}
+
+	ret := r.slabs.newFunctionType()
+	*ret = FunctionType{
 		InputType:  in,
 		ReturnType: out,
-	}, nil
+	}
+	return ret, nil
 }
 
 type TableType struct {
-	ElemType byte
+	ElemType ValueType
 	Limit    *LimitType
 }
 
-func readTableType(r io.Reader) (*TableType, error) {
-	et, err := ReadByte(r)
+func readTableType(r *reader) (*TableType, error) {
+	b, err := r.ReadByte()
 	if err != nil {
 		return nil, fmt.Errorf("read element type: %w", err)
 	}
 
-	// TODO WASM 1.0 defines that element type must be 0x70(function ref)
-	if et != ElemTypeFuncRef {
-		return nil, fmt.Errorf("read element type: not be 0x70, which is defined by WASM 1.0")
+	switch et := ValueType(b); et {
+	case ElemTypeFuncRef:
+	case ElemTypeExternRef:
+		if err := r.require(feature.ReferenceTypes, "table of externref"); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid byte for element type: %#x", b)
 	}
 
 	l, err := readLimitType(r)
@@ -151,7 +232,7 @@ func readTableType(r io.Reader) (*TableType, error) {
 	}
 
 	return &TableType{
-		ElemType: et,
+		ElemType: ValueType(b),
 		Limit:    l,
 	}, nil
 }
@@ -162,53 +243,93 @@ type LimitType struct {
 	Max uint32
 }
 
-func readLimitType(r io.Reader) (*LimitType, error) {
-	b, err := ReadByte(r)
+// HasMax reports whether Max is defined
+func (l *LimitType) HasMax() bool {
+	return l.Tag&LimitTypeBothMinAndMax != 0
+}
+
+// Shared reports whether the limits are of a shared memory
+func (l *LimitType) Shared() bool {
+	return l.Tag&LimitTypeShared != 0
+}
+
+// Is64 reports whether the limits are of a 64-bit memory
+func (l *LimitType) Is64() bool {
+	return l.Tag&LimitType64 != 0
+}
+
+func readLimitType(r *reader) (*LimitType, error) {
+	b, err := r.ReadByte()
 	if err != nil {
 		return nil, fmt.Errorf("read limits type tag: %w", err)
 	}
 
-	ret := &LimitType{
-		Tag: b,
+	if b&^(LimitTypeBothMinAndMax|LimitTypeShared|LimitType64) != 0 {
+		return nil, fmt.Errorf("invalid byte for limit type tag: %#x", b)
 	}
-	switch b {
-	case LimitTypeOnlyMin:
-		ret.Min, _, err = common.DecodeUint32(r)
-		if err != nil {
-			return nil, fmt.Errorf("read min of limit: %w", err)
+
+	ret := r.slabs.newLimitType()
+	ret.Tag = b
+	if ret.Shared() {
+		if err := r.require(feature.Threads, "shared memory"); err != nil {
+			return nil, err
 		}
-	case LimitTypeBothMinAndMax:
-		ret.Min, _, err = common.DecodeUint32(r)
-		if err != nil {
-			return nil, fmt.Errorf("read min of limit: %w", err)
+		if !ret.HasMax() {
+			return nil, fmt.Errorf("shared memory must have a max")
+		}
+	}
+	if ret.Is64() {
+		if err := r.require(feature.Memory64, "64-bit memory"); err != nil {
+			return nil, err
 		}
-		ret.Max, _, err = common.DecodeUint32(r)
+	}
+
+	ret.Min, err = readLimit(r, ret.Is64())
+	if err != nil {
+		return nil, fmt.Errorf("read min of limit: %w", err)
+	}
+	if ret.HasMax() {
+		ret.Max, err = readLimit(r, ret.Is64())
 		if err != nil {
 			return nil, fmt.Errorf("read max of limit: %w", err)
 		}
-	default:
-		return nil, fmt.Errorf("invalid byte for limit type tag: %#x != 0x00 or 0x01", b)
 	}
 
 	return ret, nil
 }
 
+// readLimit read a bound of limits, which is encoded as u64 for 64-bit memory
+func readLimit(r *reader, is64 bool) (uint32, error) {
+	if !is64 {
+		v, err := r.readUint32()
+		return v, err
+	}
+
+	v, err := r.readUint64()
+	if err != nil {
+		return 0, err
+	}
+	if v > math.MaxUint32 {
+		return 0, fmt.Errorf("64-bit limit %d exceeds the supported range", v)
+	}
+	return uint32(v), nil
+}
+
 type GlobalType struct {
 	Value   ValueType
 	Mutable bool
 }
 
-func readGlobalType(r io.Reader) (*GlobalType, error) {
+func readGlobalType(r *reader) (*GlobalType, error) {
 	vt, err := readValueType(r)
 	if err != nil {
 		return nil, fmt.Errorf("read value type: %w", err)
 	}
 
-	ret := &GlobalType{
-		Value: vt,
-	}
+	ret := r.slabs.newGlobalType()
+	ret.Value = vt
 
-	mut, err := ReadByte(r)
+	mut, err := r.ReadByte()
 	if err != nil {
 		return nil, fmt.Errorf("read mutablity: %w", err)
 	}
@@ -227,7 +348,7 @@ func readGlobalType(r io.Reader) (*GlobalType, error) {
 
 type MemoryType = LimitType
 
-func readMemoryType(r io.Reader) (*MemoryType, error) {
+func readMemoryType(r *reader) (*MemoryType, error) {
 	return readLimitType(r)
 }
 
@@ -236,8 +357,8 @@ type LocalValueType struct {
 	Type  ValueType
 }
 
-func readLocalValueType(r io.Reader) (*LocalValueType, error) {
-	c, _, err := common.DecodeUint32(r)
+func readLocalValueType(r *reader) (*LocalValueType, error) {
+	c, err := r.readUint32()
 	if err != nil {
 		return nil, fmt.Errorf("read number of locals: %w", err)
 	}
@@ -247,10 +368,12 @@ func readLocalValueType(r io.Reader) (*LocalValueType, error) {
 		return nil, fmt.Errorf("read value type of locals: %w", err)
 	}
 
-	return &LocalValueType{
+	ret := r.slabs.newLocalValueType()
+	*ret = LocalValueType{
 		Count: c,
 		Type:  vt,
-	}, nil
+	}
+	return ret, nil
 }
 
 type CodeSegmentBody []byte
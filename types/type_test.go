@@ -0,0 +1,40 @@
+package types_test
+
+import (
+	"github.com/LBruyne/wasm-decode/builder"
+	"github.com/LBruyne/wasm-decode/decode"
+	"github.com/LBruyne/wasm-decode/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGlobalMutability(t *testing.T) {
+	b := builder.New()
+	b.AddGlobal(types.ValueTypeI32, true, types.NewI32Const(1))
+	b.AddGlobal(types.ValueTypeI64, false, types.NewI64Const(2))
+	bs, err := b.Bytes()
+	assert.Nil(t, err)
+
+	mod, err := decode.DecodeBytes(bs)
+	assert.Nil(t, err)
+	if assert.Len(t, mod.SecGlobal, 2) {
+		assert.True(t, mod.SecGlobal[0].Type.Mutable)
+		assert.False(t, mod.SecGlobal[1].Type.Mutable)
+	}
+
+	// the mutability byte follows the value type, 0x01 for var and 0x00 for const
+	sec := mod.Sections[0]
+	assert.Equal(t, types.SectionIDGlobal, sec.ID)
+	payload := bs[sec.PayloadOffset:sec.End()]
+	assert.Equal(t, []byte{0x02, 0x7f, 0x01}, payload[:3])
+	assert.Equal(t, []byte{0x7e, 0x00}, payload[6:8])
+
+	// the stack pointer of test.wasm is the only mutable global
+	mod, err = decode.DecodeFile(exampleFile)
+	assert.Nil(t, err)
+	if assert.Len(t, mod.SecGlobal, 3) {
+		assert.True(t, mod.SecGlobal[0].Type.Mutable)
+		assert.False(t, mod.SecGlobal[1].Type.Mutable)
+		assert.False(t, mod.SecGlobal[2].Type.Mutable)
+	}
+}
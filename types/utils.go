@@ -2,32 +2,74 @@ package types
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"github.com/LBruyne/wasm-decode/common"
 	"io"
 	"math"
 )
 
-// ReadString try to read a string from io.Reader
+// ReadString try to read a name from io.Reader, which must be valid UTF-8,
+// the length is limited by DefaultDecodeOptions
 func ReadString(r io.Reader) (string, error) {
-	vs, _, err := common.DecodeUint32(r)
-	if err != nil {
-		return "", fmt.Errorf("read size of string: %w", err)
+	bs, ok := r.(byteScanner)
+	if !ok {
+		// do not read ahead of the string
+		bs = &unbufferedScanner{r: r}
 	}
+	name, _, err := newStreamReader(bs, nil).readName()
+	return name, err
+}
 
-	buf := make([]byte, vs)
-	if _, err := io.ReadFull(r, buf); err != nil {
-		return "", fmt.Errorf("read bytes of string: %w", err)
+// unbufferedScanner reads byte by byte from r, which is never read beyond the bytes consumed
+type unbufferedScanner struct {
+	r      io.Reader
+	last   [1]byte
+	unread bool
+}
+
+func (s *unbufferedScanner) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if s.unread {
+		s.unread = false
+		p[0] = s.last[0]
+		return 1, nil
+	}
+	return s.r.Read(p)
+}
+
+func (s *unbufferedScanner) ReadByte() (byte, error) {
+	if s.unread {
+		s.unread = false
+		return s.last[0], nil
+	}
+	if _, err := io.ReadFull(s.r, s.last[:]); err != nil {
+		return 0, err
 	}
+	return s.last[0], nil
+}
 
-	return string(buf), nil
+func (s *unbufferedScanner) UnreadByte() error {
+	if s.unread {
+		return errors.New("UnreadByte: previous operation was not ReadByte")
+	}
+	s.unread = true
+	return nil
 }
 
 // ReadByte read one byte from io.Reader
 func ReadByte(r io.Reader) (byte, error) {
-	p := make([]byte, 1)
-	_, err := r.Read(p)
-	return p[0], err
+	if br, ok := r.(io.ByteReader); ok {
+		return br.ReadByte()
+	}
+
+	var p [1]byte
+	if _, err := io.ReadFull(r, p[:]); err != nil {
+		return 0, err
+	}
+	return p[0], nil
 }
 
 // IEEE 754
@@ -52,10 +94,15 @@ func ReadFloat64(r io.Reader) (float64, error) {
 	return math.Float64frombits(raw), nil
 }
 
-// getNumLocals count the total number of locals in one code segment
-func getNumLocals(locals []*LocalValueType) (numLocal uint32) {
+// getNumLocals count the total number of locals in one code segment, which must not exceed max
+func getNumLocals(locals []*LocalValueType, max uint32) (uint32, error) {
+	var numLocal uint64
 	for _, lt := range locals {
-		numLocal += lt.Count
+		numLocal += uint64(lt.Count)
+	}
+
+	if numLocal > math.MaxUint32 || (max != 0 && numLocal > uint64(max)) {
+		return 0, fmt.Errorf("%w: %d", common.ErrTooManyLocals, numLocal)
 	}
-	return numLocal
+	return uint32(numLocal), nil
 }
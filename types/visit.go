@@ -0,0 +1,222 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"github.com/LBruyne/wasm-decode/common"
+	"github.com/LBruyne/wasm-decode/operator"
+)
+
+var (
+	// SkipBody returned by Visitor.VisitFunction or Visitor.VisitInstruction skips the rest of the function body
+	SkipBody = errors.New("skip body")
+	// StopWalk returned by a Visitor stops the walk, Walk returns nil
+	StopWalk = errors.New("stop walk")
+)
+
+// Visitor receives the components of a module in the order of the module binary.
+// The components of the index spaces come with their index, the imported ones are visited
+// right after their import. Embed BaseVisitor to implement only some of the methods.
+type Visitor interface {
+	VisitImport(imp *ImportSegment) error
+	VisitTable(t *Table) error
+	VisitMemory(mem *Memory) error
+	VisitGlobal(g *Global) error
+	VisitExport(exp *ExportSegment) error
+	VisitElementSegment(idx uint32, e *ElementSegment) error
+	// VisitFunction is followed by VisitInstruction for each instruction of the body of a defined function
+	VisitFunction(f *Function) error
+	// VisitInstruction receives the offset of ins in the module binary
+	VisitInstruction(funcIdx uint32, offset int64, ins *operator.Instruction) error
+	VisitDataSegment(idx uint32, d *DataSegment) error
+	VisitCustomSection(c *CustomSec) error
+}
+
+// BaseVisitor visits nothing
+type BaseVisitor struct{}
+
+func (BaseVisitor) VisitImport(*ImportSegment) error                            { return nil }
+func (BaseVisitor) VisitTable(*Table) error                                     { return nil }
+func (BaseVisitor) VisitMemory(*Memory) error                                   { return nil }
+func (BaseVisitor) VisitGlobal(*Global) error                                   { return nil }
+func (BaseVisitor) VisitExport(*ExportSegment) error                            { return nil }
+func (BaseVisitor) VisitElementSegment(uint32, *ElementSegment) error           { return nil }
+func (BaseVisitor) VisitFunction(*Function) error                               { return nil }
+func (BaseVisitor) VisitInstruction(uint32, int64, *operator.Instruction) error { return nil }
+func (BaseVisitor) VisitDataSegment(uint32, *DataSegment) error                 { return nil }
+func (BaseVisitor) VisitCustomSection(*CustomSec) error                         { return nil }
+
+// Walk visits the components of m with v, see DecodeOptions.Visitor to visit them while decoding
+func Walk(m *Module, v Visitor) error {
+	w := &walker{m: m, v: v}
+	err := w.sections()
+	if errors.Is(err, StopWalk) {
+		return nil
+	}
+	return err
+}
+
+// canonicalOrder is the order of the sections of a module which is not decoded
+var canonicalOrder = []SectionID{SectionIDImport, SectionIDTable, SectionIDMemory, SectionIDGlobal,
+	SectionIDExport, SectionIDElement, SectionIDCode, SectionIDData}
+
+// walker visits the components of m section by section
+type walker struct {
+	m        *Module
+	v        Visitor
+	customs  int    // number of custom sections visited
+	funcBase uint32 // number of imported functions, known once the imports are visited
+}
+
+func (w *walker) sections() error {
+	if len(w.m.Sections) == 0 {
+		for _, id := range canonicalOrder {
+			if err := w.section(id); err != nil {
+				return err
+			}
+		}
+		for range w.m.SecCustoms {
+			if err := w.section(SectionIDCustom); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, s := range w.m.Sections {
+		if err := w.section(s.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// section visits the components of the section of id, the custom sections are visited in order
+func (w *walker) section(id SectionID) error {
+	m := w.m
+	switch id {
+	case SectionIDCustom:
+		if w.customs >= len(m.SecCustoms) {
+			return nil
+		}
+		w.customs++
+		return w.v.VisitCustomSection(m.SecCustoms[w.customs-1])
+	case SectionIDImport:
+		return w.imports()
+	case SectionIDTable:
+		base := m.ImportedCount(ExternalKindTable)
+		for i, t := range m.SecTable {
+			if err := w.v.VisitTable(&Table{Index: base + uint32(i), Type: t}); err != nil {
+				return err
+			}
+		}
+	case SectionIDMemory:
+		base := m.ImportedCount(ExternalKindMemory)
+		for i, mem := range m.SecMemory {
+			if err := w.v.VisitMemory(&Memory{Index: base + uint32(i), Type: mem}); err != nil {
+				return err
+			}
+		}
+	case SectionIDGlobal:
+		base := m.ImportedCount(ExternalKindGlobal)
+		for i, g := range m.SecGlobal {
+			if err := w.v.VisitGlobal(&Global{Index: base + uint32(i), Type: g.Type, Init: g.Init}); err != nil {
+				return err
+			}
+		}
+	case SectionIDExport:
+		for _, exp := range m.SecExport {
+			if err := w.v.VisitExport(exp); err != nil {
+				return err
+			}
+		}
+	case SectionIDElement:
+		for i, e := range m.SecElement {
+			if err := w.v.VisitElementSegment(uint32(i), e); err != nil {
+				return err
+			}
+		}
+	case SectionIDCode:
+		for i := range m.SecFunction {
+			if err := w.function(i); err != nil {
+				return err
+			}
+		}
+	case SectionIDData:
+		for i, d := range m.SecData {
+			if err := w.v.VisitDataSegment(uint32(i), d); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *walker) imports() error {
+	w.funcBase = w.m.importedFuncCount()
+	var counts [4]uint32
+	for _, imp := range w.m.SecImport {
+		if err := w.v.VisitImport(imp); err != nil {
+			return err
+		}
+
+		kind := imp.Desc.Kind()
+		if int(kind) >= len(counts) {
+			continue
+		}
+		idx := counts[kind]
+		counts[kind]++
+
+		var err error
+		switch desc := imp.Desc.(type) {
+		case *FuncImport:
+			err = w.v.VisitFunction(w.m.importedFunction(idx, imp))
+		case *TableImport:
+			err = w.v.VisitTable(&Table{Index: idx, Type: desc.Type, Import: imp})
+		case *MemoryImport:
+			err = w.v.VisitMemory(&Memory{Index: idx, Type: desc.Type, Import: imp})
+		case *GlobalImport:
+			err = w.v.VisitGlobal(&Global{Index: idx, Type: desc.Type, Import: imp})
+		}
+		if err != nil && err != SkipBody {
+			return err
+		}
+	}
+	return nil
+}
+
+// function visits the i-th defined function and the instructions of its body
+func (w *walker) function(i int) error {
+	if i >= len(w.m.SecFunction) {
+		return fmt.Errorf("%w: code segment %d of %d functions", common.ErrIndexOutOfRange, i, len(w.m.SecFunction))
+	}
+	f := w.m.definedFunction(w.funcBase+uint32(i), i)
+	if err := w.v.VisitFunction(f); err != nil {
+		if err == SkipBody {
+			return nil
+		}
+		return err
+	}
+	if f.Code == nil {
+		return nil
+	}
+
+	body, err := f.Code.LoadBody()
+	if err != nil {
+		return fmt.Errorf("load body of func[%d]: %w", f.Index, err)
+	}
+	for off := 0; off < len(body); {
+		ins, n, err := operator.ReadInstruction(body[off:])
+		if err != nil {
+			return fmt.Errorf("read instruction of func[%d] at %#x: %w", f.Index, f.Code.BodyOffset+int64(off), err)
+		}
+		if err := w.v.VisitInstruction(f.Index, f.Code.BodyOffset+int64(off), ins); err != nil {
+			if err == SkipBody {
+				return nil
+			}
+			return err
+		}
+		off += n
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package types_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/LBruyne/wasm-decode/decode"
+	"github.com/LBruyne/wasm-decode/operator"
+	"github.com/LBruyne/wasm-decode/types"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+// countVisitor counts the components it visits
+type countVisitor struct {
+	types.BaseVisitor
+	funcs, imported, instrs, data, customs int
+	offsets                                []int64
+	skip                                   uint32
+	stopAt                                 int
+}
+
+func (v *countVisitor) VisitFunction(f *types.Function) error {
+	v.funcs++
+	if f.Imported() {
+		v.imported++
+	}
+	if f.Index == v.skip {
+		return types.SkipBody
+	}
+	return nil
+}
+
+func (v *countVisitor) VisitInstruction(funcIdx uint32, offset int64, ins *operator.Instruction) error {
+	v.instrs++
+	v.offsets = append(v.offsets, offset)
+	if v.instrs == v.stopAt {
+		return types.StopWalk
+	}
+	return nil
+}
+
+func (v *countVisitor) VisitDataSegment(uint32, *types.DataSegment) error {
+	v.data++
+	return nil
+}
+
+func (v *countVisitor) VisitCustomSection(*types.CustomSec) error {
+	v.customs++
+	return nil
+}
+
+func TestWalk(t *testing.T) {
+	buf, err := ioutil.ReadFile(exampleFile)
+	assert.Nil(t, err)
+	mod, err := decode.DecodeBytes(buf)
+	assert.Nil(t, err)
+
+	v := &countVisitor{skip: ^uint32(0)}
+	assert.Nil(t, types.Walk(mod, v))
+	assert.Equal(t, len(mod.Functions()), v.funcs)
+	assert.Equal(t, int(mod.ImportedCount(types.ExternalKindFunc)), v.imported)
+	assert.Equal(t, len(mod.SecData), v.data)
+	assert.Equal(t, len(mod.SecCustoms), v.customs)
+	assert.NotZero(t, v.instrs)
+
+	// the body of the last function ends by the end OpCode
+	last := mod.SecCode[len(mod.SecCode)-1]
+	end := v.offsets[len(v.offsets)-1]
+	assert.Equal(t, byte(operator.OpCodeEnd), buf[end])
+	assert.Equal(t, last.BodyOffset+int64(last.BodySize)-1, end)
+
+	// the same components are visited while decoding, without keeping the bodies
+	sv := &countVisitor{skip: ^uint32(0)}
+	opts := types.DefaultDecodeOptions()
+	opts.Visitor = sv
+	streamed, err := decode.DecodeModuleWithOptions(bytes.NewReader(buf), opts)
+	assert.Nil(t, err)
+	assert.Equal(t, v, sv)
+	for _, c := range streamed.SecCode {
+		assert.Nil(t, c)
+	}
+
+	// the body of the first defined function is skipped
+	first := mod.ImportedCount(types.ExternalKindFunc)
+	sk := &countVisitor{skip: first}
+	assert.Nil(t, types.Walk(mod, sk))
+	assert.Equal(t, v.funcs, sk.funcs)
+	assert.Less(t, sk.instrs, v.instrs)
+
+	stop := &countVisitor{skip: ^uint32(0), stopAt: 3}
+	assert.Nil(t, types.Walk(mod, stop))
+	assert.Equal(t, 3, stop.instrs)
+	assert.Zero(t, stop.data)
+
+	stop = &countVisitor{skip: ^uint32(0), stopAt: 3}
+	opts.Visitor = stop
+	_, err = decode.DecodeBytesWithOptions(buf, opts)
+	assert.True(t, errors.Is(err, types.StopWalk))
+	assert.Equal(t, 3, stop.instrs)
+}